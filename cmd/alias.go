@@ -0,0 +1,191 @@
+// cmd/alias.go
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/yogendratamang48/ecs/pkg/config"
+)
+
+// expandAliases replaces a leading alias in the command line with the command
+// it stands for, like git aliases. Built-in commands always win over aliases,
+// and an alias may expand to another alias.
+func expandAliases(args []string) ([]string, error) {
+	if len(args) > 0 && (args[0] == cobra.ShellCompRequestCmd || args[0] == cobra.ShellCompNoDescRequestCmd) {
+		return args, nil
+	}
+
+	var aliases map[string]string
+	seen := make(map[string]bool)
+	for {
+		i := commandIndex(args)
+		if i < 0 || isBuiltinCommand(args[i]) {
+			return args, nil
+		}
+
+		if aliases == nil {
+			files, err := config.SearchPath(flagValue(args, "config"))
+			if err != nil {
+				return args, nil
+			}
+			m, err := config.NewManager(files...)
+			if err != nil {
+				return args, nil
+			}
+			aliases = m.Aliases()
+		}
+
+		name := args[i]
+		expansion, ok := aliases[name]
+		if !ok {
+			return args, nil
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("alias '%s' expands to itself", name)
+		}
+		seen[name] = true
+
+		words, err := splitArgs(expansion)
+		if err != nil {
+			return nil, fmt.Errorf("alias '%s': %w", name, err)
+		}
+		if len(words) == 0 {
+			return nil, fmt.Errorf("alias '%s' must expand to a command", name)
+		}
+
+		expanded := append([]string{}, args[:i]...)
+		expanded = append(expanded, words...)
+		args = append(expanded, args[i+1:]...)
+	}
+}
+
+// commandIndex returns the position of the first command word, skipping
+// global flags and their values, or -1 if there is none
+func commandIndex(args []string) int {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return -1
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			return i
+		}
+		if strings.Contains(arg, "=") {
+			continue
+		}
+
+		var flag *pflag.Flag
+		if strings.HasPrefix(arg, "--") {
+			flag = rootCmd.PersistentFlags().Lookup(arg[2:])
+		} else if len(arg) == 2 {
+			flag = rootCmd.PersistentFlags().ShorthandLookup(arg[1:])
+		}
+		if flag != nil && flag.NoOptDefVal == "" {
+			i++
+		}
+	}
+	return -1
+}
+
+// isBuiltinCommand reports whether name is a command of the CLI itself
+func isBuiltinCommand(name string) bool {
+	rootCmd.InitDefaultHelpCmd()
+	rootCmd.InitDefaultCompletionCmd()
+
+	cmd, _, err := rootCmd.Find([]string{name})
+	return err == nil && cmd != rootCmd
+}
+
+// flagValue returns the value of a long flag in the command line before
+// it has been parsed
+func flagValue(args []string, name string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if arg == "--"+name && i+1 < len(args) {
+			return args[i+1]
+		}
+		if value, ok := strings.CutPrefix(arg, "--"+name+"="); ok {
+			return value
+		}
+	}
+	return ""
+}
+
+// splitArgs splits a command line into words like a POSIX shell does,
+// honouring single quotes, double quotes and backslash escapes
+func splitArgs(s string) ([]string, error) {
+	var (
+		words   []string
+		word    strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+
+	for _, r := range s {
+		switch {
+		case escaped:
+			if quote == '"' && !strings.ContainsRune(`"\$`+"`", r) {
+				word.WriteRune('\\')
+			}
+			word.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\\':
+			escaped = true
+			inWord = true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if escaped {
+		return nil, fmt.Errorf("trailing backslash")
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// joinArgs is the inverse of splitArgs, quoting words where needed
+func joinArgs(words []string) string {
+	quoted := make([]string, len(words))
+	for i, w := range words {
+		if w != "" && !strings.ContainsAny(w, " \t\n'\"\\$`") {
+			quoted[i] = w
+			continue
+		}
+		quoted[i] = "'" + strings.ReplaceAll(w, "'", `'\''`) + "'"
+	}
+	return strings.Join(quoted, " ")
+}
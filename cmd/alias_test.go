@@ -0,0 +1,80 @@
+// cmd/alias_test.go
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"get services", []string{"get", "services"}},
+		{"  get \t services\n", []string{"get", "services"}},
+		{`logs web --since '1 hour'`, []string{"logs", "web", "--since", "1 hour"}},
+		{`exec "my svc" -- sh -c "echo \"hi\""`, []string{"exec", "my svc", "--", "sh", "-c", `echo "hi"`}},
+		{`a\ b c`, []string{"a b", "c"}},
+		{`"a\b"`, []string{`a\b`}},
+		{`'a\b'`, []string{`a\b`}},
+		{`'' ""`, []string{"", ""}},
+		{`'it'\''s'`, []string{"it's"}},
+		{`"$HOME"`, []string{"$HOME"}},
+	}
+	for _, tt := range tests {
+		got, err := splitArgs(tt.in)
+		if err != nil {
+			t.Errorf("splitArgs(%q): %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitArgs(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSplitArgsErrors(t *testing.T) {
+	for _, in := range []string{`get \`, `logs 'web`, `logs "web`} {
+		if _, err := splitArgs(in); err == nil {
+			t.Errorf("splitArgs(%q) succeeded, want an error", in)
+		}
+	}
+}
+
+func TestJoinArgs(t *testing.T) {
+	tests := []struct {
+		in   []string
+		want string
+	}{
+		{[]string{"get", "services"}, "get services"},
+		{[]string{"logs", "--since", "1 hour"}, "logs --since '1 hour'"},
+		{[]string{""}, "''"},
+		{[]string{"it's"}, `'it'\''s'`},
+		{[]string{`a\b`, "$HOME"}, `'a\b' '$HOME'`},
+	}
+	for _, tt := range tests {
+		if got := joinArgs(tt.in); got != tt.want {
+			t.Errorf("joinArgs(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestJoinArgsRoundTrip(t *testing.T) {
+	words := [][]string{
+		{"get", "tasks", "-o", "wide"},
+		{"exec", "web", "--", "sh", "-c", `echo "$HOME" 'x'`},
+		{"a b", "", "tab\there", "new\nline", `back\slash`, "`tick`"},
+	}
+	for _, w := range words {
+		got, err := splitArgs(joinArgs(w))
+		if err != nil {
+			t.Errorf("splitArgs(joinArgs(%q)): %v", w, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, w) {
+			t.Errorf("splitArgs(joinArgs(%q)) = %q", w, got)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+// cmd/backend.go
+package cmd
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/yogendratamang48/ecs/pkg/aws"
+	"github.com/yogendratamang48/ecs/pkg/fake"
+)
+
+// Backend flags, set by --backend and --fixture
+var (
+	backendName string
+	fixtureFile string
+)
+
+// The fake backend is created once per invocation, so that every session
+// (e.g. one per context when querying several contexts) shares its state
+var (
+	fakeBackendOnce sync.Once
+	fakeBackend     *fake.Backend
+	fakeBackendErr  error
+)
+
+// selectedBackend returns the backend chosen with --backend, or nil when
+// requests go to AWS. --fixture alone implies the fake backend.
+func selectedBackend() (aws.Backend, error) {
+	name := backendName
+	if name == "" && fixtureFile != "" {
+		name = "fake"
+	}
+
+	switch name {
+	case "", "aws":
+		if fixtureFile != "" {
+			return nil, fmt.Errorf("--fixture requires --backend fake")
+		}
+		return nil, nil
+	case "fake":
+		fakeBackendOnce.Do(func() {
+			if fixtureFile == "" {
+				fakeBackend = fake.NewDemo()
+				return
+			}
+			fixture, err := fake.LoadFixture(fixtureFile)
+			if err != nil {
+				fakeBackendErr = fmt.Errorf("failed to load fixture: %w", err)
+				return
+			}
+			fakeBackend = fake.New(fixture)
+		})
+		if fakeBackendErr != nil {
+			return nil, fakeBackendErr
+		}
+		return fakeBackend, nil
+	default:
+		return nil, fmt.Errorf("unknown backend %q, must be aws or fake", name)
+	}
+}
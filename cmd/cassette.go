@@ -0,0 +1,71 @@
+// cmd/cassette.go
+package cmd
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/yogendratamang48/ecs/pkg/aws"
+)
+
+// Cassette flags, set by --record and --replay
+var (
+	recordFile string
+	replayFile string
+)
+
+// The recorder and replayer are created once per invocation and shared by
+// every session, so that a cassette covers all the contexts a command uses
+var (
+	recorderOnce sync.Once
+	recorder     *aws.Recorder
+	recorderErr  error
+
+	replayerOnce sync.Once
+	replayer     *aws.Replayer
+	replayerErr  error
+)
+
+// cassetteOptions sets the recorder or replayer chosen with --record or
+// --replay on opts
+func cassetteOptions(opts *aws.Options) error {
+	if recordFile == "" && replayFile == "" {
+		return nil
+	}
+	if recordFile != "" && replayFile != "" {
+		return fmt.Errorf("--record and --replay cannot be used together")
+	}
+	if opts.Backend != nil {
+		return fmt.Errorf("--record and --replay cannot be used with the fake backend")
+	}
+
+	if recordFile != "" {
+		recorderOnce.Do(func() {
+			recorder, recorderErr = aws.NewRecorder(recordFile)
+		})
+		opts.Recorder = recorder
+		return recorderErr
+	}
+
+	replayerOnce.Do(func() {
+		replayer, replayerErr = aws.NewReplayer(replayFile)
+		if replayerErr != nil {
+			replayerErr = fmt.Errorf("failed to load cassette: %w", replayerErr)
+		}
+	})
+	opts.Replayer = replayer
+	return replayerErr
+}
+
+// saveRecording writes the cassette of --record, if any requests were made.
+// It runs after the command, whether it failed or not, since recordings of
+// failures are the useful ones.
+func saveRecording() error {
+	if recorder == nil {
+		return nil
+	}
+	if err := recorder.Save(); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+// cmd/commands_test.go
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// run executes the CLI against the fake backend and returns what it printed.
+// Every test uses a cluster of its own, since the fake backend is shared by
+// the whole test binary.
+func run(t *testing.T, args ...string) string {
+	t.Helper()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("ECS_CONFIG", filepath.Join(home, "config.yaml"))
+	t.Setenv("ECS_CONTEXT", "")
+	t.Setenv("ECS_CLUSTER", "")
+	resetFlags(rootCmd)
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	out := make(chan string)
+	go func() {
+		var buf bytes.Buffer
+		io.Copy(&buf, r)
+		out <- buf.String()
+	}()
+
+	rootCmd.SetArgs(append([]string{"--backend", "fake"}, args...))
+	err = rootCmd.ExecuteContext(context.Background())
+	w.Close()
+	printed := <-out
+	if err != nil {
+		t.Fatalf("ecs %s: %v", strings.Join(args, " "), err)
+	}
+	return printed
+}
+
+// resetFlags restores the flags of cmd and its subcommands to their defaults,
+// since cobra keeps the values of a previous execution
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if !f.Changed {
+			return
+		}
+		if v, ok := f.Value.(pflag.SliceValue); ok {
+			v.Replace(nil)
+		} else {
+			f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, c := range cmd.Commands() {
+		resetFlags(c)
+	}
+}
+
+type serviceRow struct {
+	Name         string `json:"name"`
+	DesiredCount int32  `json:"desiredCount"`
+	RunningCount int32  `json:"runningCount"`
+}
+
+type taskRow struct {
+	TaskID        string `json:"taskId"`
+	DesiredStatus string `json:"desiredStatus"`
+	Group         string `json:"group"`
+}
+
+func getServices(t *testing.T, cluster string) map[string]serviceRow {
+	t.Helper()
+	var rows []serviceRow
+	if err := json.Unmarshal([]byte(run(t, "get", "services", "--cluster", cluster, "-o", "json")), &rows); err != nil {
+		t.Fatal(err)
+	}
+	services := make(map[string]serviceRow)
+	for _, row := range rows {
+		services[row.Name] = row
+	}
+	return services
+}
+
+func getTasks(t *testing.T, cluster string) []taskRow {
+	t.Helper()
+	var rows []taskRow
+	if err := json.Unmarshal([]byte(run(t, "get", "tasks", "--cluster", cluster, "-o", "json")), &rows); err != nil {
+		t.Fatal(err)
+	}
+	return rows
+}
+
+func TestGetServices(t *testing.T) {
+	services := getServices(t, "get-services")
+
+	want := map[string]int32{"web": 3, "api": 2, "worker": 1}
+	if len(services) != len(want) {
+		t.Fatalf("got %d services, want %d", len(services), len(want))
+	}
+	for name, desired := range want {
+		svc, ok := services[name]
+		if !ok {
+			t.Fatalf("service %s missing", name)
+		}
+		if svc.DesiredCount != desired || svc.RunningCount != desired {
+			t.Errorf("%s: desired %d running %d, want %d", name, svc.DesiredCount, svc.RunningCount, desired)
+		}
+	}
+
+	table := run(t, "get", "services", "--cluster", "get-services")
+	for _, name := range []string{"NAME", "web", "api", "worker"} {
+		if !strings.Contains(table, name) {
+			t.Errorf("table is missing %q:\n%s", name, table)
+		}
+	}
+}
+
+func TestScaleThenGet(t *testing.T) {
+	const cluster = "scale"
+
+	tests := []struct {
+		replicas string
+		want     int32
+	}{
+		{"5", 5},
+		{"1", 1},
+		{"0", 0},
+	}
+	for _, tt := range tests {
+		out := run(t, "scale", "web", "--replicas", tt.replicas, "--cluster", cluster)
+		if !strings.Contains(out, "Successfully scaled service web") {
+			t.Errorf("unexpected scale output: %q", out)
+		}
+
+		web := getServices(t, cluster)["web"]
+		if web.DesiredCount != tt.want || web.RunningCount != tt.want {
+			t.Errorf("after scaling to %s: desired %d running %d", tt.replicas, web.DesiredCount, web.RunningCount)
+		}
+
+		running := 0
+		for _, task := range getTasks(t, cluster) {
+			if task.Group == "service:web" {
+				running++
+			}
+		}
+		if int32(running) != tt.want {
+			t.Errorf("after scaling to %s: %d web tasks listed", tt.replicas, running)
+		}
+	}
+}
+
+func TestStopTask(t *testing.T) {
+	const cluster = "stop-task"
+
+	before := getTasks(t, cluster)
+	var stopped string
+	for _, task := range before {
+		if task.Group == "service:worker" {
+			stopped = task.TaskID
+		}
+	}
+	if stopped == "" {
+		t.Fatal("no worker task running")
+	}
+
+	out := run(t, "delete", "task", stopped, "--cluster", cluster)
+	if !strings.Contains(out, "Task "+stopped+" stopped") {
+		t.Errorf("unexpected delete output: %q", out)
+	}
+
+	// The service replaces the stopped task, keeping the number of tasks
+	after := getTasks(t, cluster)
+	if len(after) != len(before) {
+		t.Errorf("got %d tasks after stopping one, want %d", len(after), len(before))
+	}
+	replaced := false
+	for _, task := range after {
+		if task.TaskID == stopped {
+			t.Errorf("stopped task %s is still listed", stopped)
+		}
+		if task.Group == "service:worker" {
+			replaced = true
+		}
+	}
+	if !replaced {
+		t.Error("worker task was not replaced")
+	}
+
+	if worker := getServices(t, cluster)["worker"]; worker.RunningCount != 1 {
+		t.Errorf("worker running %d, want 1", worker.RunningCount)
+	}
+}
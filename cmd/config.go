@@ -10,53 +10,24 @@ import (
 	"text/tabwriter"
 
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
+	"github.com/spf13/pflag"
 	"github.com/yogendratamang48/ecs/pkg/config"
 	"github.com/yogendratamang48/ecs/pkg/types"
 )
 
-var configManager *config.Manager
+var (
+	configManager *config.Manager
+	configFiles   string
+)
 
-func init() {
-	configManager = config.NewManager()
-}
+// initConfig loads the config files from --config, ECS_CONFIG or the default
+// location once flags have been parsed
 func initConfig() {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		panic(err)
-	}
-	configDir := filepath.Join(home, ".ecs")
-
-	if err := os.MkdirAll(configDir, 0755); err != nil {
-		panic(err)
-	}
-	configFile := filepath.Join(configDir, "config.yaml")
-	viper.SetConfigFile(configFile)
-	viper.SetConfigType("yaml")
-
-	// If the config file doesn't exist, create it with default settings
-	if _, err := os.Stat(configFile); os.IsNotExist(err) {
-		// Create empty config file
-		file, err := os.Create(configFile)
-		if err != nil {
-			panic(err)
-		}
-		file.Close()
+	files, err := config.SearchPath(configFiles)
+	cobra.CheckErr(err)
 
-		// Initialize with empty contexts
-		viper.Set("contexts", map[string]interface{}{})
-		viper.Set("current-context", "")
-
-		// Write initial config
-		if err := viper.WriteConfig(); err != nil {
-			panic(err)
-		}
-	}
-
-	// Read the config file
-	if err := viper.ReadInConfig(); err != nil {
-		panic(err)
-	}
+	configManager, err = config.NewManager(files...)
+	cobra.CheckErr(err)
 }
 
 func configCmd() *cobra.Command {
@@ -69,15 +40,56 @@ func configCmd() *cobra.Command {
 	cmd.AddCommand(configSetContextCmd())
 	cmd.AddCommand(configGetContextsCmd())
 	cmd.AddCommand(configUseContextCmd())
+	cmd.AddCommand(configShellInitCmd())
 	cmd.AddCommand(configDeleteContextCmd())
 	cmd.AddCommand(configCurrentContextCmd())
+	cmd.AddCommand(configRenameContextCmd())
+	cmd.AddCommand(configCopyContextCmd())
+	cmd.AddCommand(configSetCmd())
 	cmd.AddCommand(configViewCmd())
+	cmd.AddCommand(configDoctorCmd())
+	cmd.AddCommand(configDiscoverCmd())
+	cmd.AddCommand(configExportCmd())
+	cmd.AddCommand(configImportCmd())
+	cmd.AddCommand(configAliasCmd())
 
 	return cmd
 }
 
+// contextFlagKeys maps set-context flags to the config keys they set
+var contextFlagKeys = map[string]string{
+	"cluster":                 "cluster",
+	"profile":                 "profile",
+	"region":                  "region",
+	"role-arn":                "role-arn",
+	"external-id":             "external-id",
+	"session-name":            "session-name",
+	"session-duration":        "session-duration",
+	"mfa-serial":              "mfa-serial",
+	"endpoint-url":            "endpoint-url",
+	"ecs-endpoint-url":        "endpoints.ecs",
+	"logs-endpoint-url":       "endpoints.logs",
+	"ssm-endpoint-url":        "endpoints.ssm",
+	"protected":               "protected",
+	"credentials-source":      "credentials.source",
+	"credential-process":      "credentials.process",
+	"web-identity-token-file": "credentials.web-identity-token-file",
+	"sso-start-url":           "sso.start-url",
+	"sso-region":              "sso.region",
+	"sso-account-id":          "sso.account-id",
+	"sso-role-name":           "sso.role-name",
+	"sso-session":             "sso.session",
+	"retry-max-attempts":      "retry.max-attempts",
+	"retry-mode":              "retry.mode",
+	"rate-limit":              "retry.rate-limit",
+	"rate-burst":              "retry.burst",
+}
+
 func configSetContextCmd() *cobra.Command {
-	var ctx types.Context
+	var (
+		ctx   types.Context
+		merge bool
+	)
 
 	cmd := &cobra.Command{
 		Use:   "set-context NAME",
@@ -86,19 +98,73 @@ func configSetContextCmd() *cobra.Command {
 
 Example:
   # Set a context named "prod" for production cluster
-  ecs config set-context prod --cluster production-cluster --profile prod-profile --region us-west-2`,
+  ecs config set-context prod --cluster production-cluster --profile prod-profile --region us-west-2
+
+  # Reach a cluster in another account by assuming a role with MFA
+  ecs config set-context prod --cluster production-cluster --profile base \
+    --role-arn arn:aws:iam::123456789012:role/ecs-operator --mfa-serial arn:aws:iam::111111111111:mfa/me
+
+  # Use the credentials of the environment, e.g. on a CI runner
+  ecs config set-context ci --cluster production-cluster --credentials-source env
+
+  # Exchange a CI OIDC token for a role
+  ecs config set-context ci --cluster production-cluster --credentials-source web-identity \
+    --role-arn arn:aws:iam::123456789012:role/ci-deployer --web-identity-token-file /var/run/oidc/token
+
+  # Use IAM Identity Center credentials, then sign in with 'ecs login'
+  ecs config set-context prod --cluster production-cluster --region us-west-2 \
+    --sso-start-url https://my-org.awsapps.com/start --sso-region us-east-1 \
+    --sso-account-id 123456789012 --sso-role-name ECSOperator
+
+  # Point a context at LocalStack
+  ecs config set-context local --cluster demo --endpoint-url http://localhost:4566
+
+  # Change only the region of an existing context
+  ecs config set-context prod --merge --region eu-west-1
+
+  # Stay under the account's API quota on a cluster with thousands of tasks
+  ecs config set-context big --merge --retry-mode adaptive --retry-max-attempts 10 --rate-limit 20
+
+  # Ask for confirmation before scaling or stopping anything in prod
+  ecs config set-context prod --merge --protected`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx.Name = args[0]
 
+			if merge {
+				if _, err := configManager.GetContextByName(ctx.Name); err != nil {
+					return err
+				}
+
+				fields := make(map[string]string)
+				cmd.Flags().Visit(func(f *pflag.Flag) {
+					if key, ok := contextFlagKeys[f.Name]; ok {
+						fields[key] = f.Value.String()
+					}
+				})
+				if err := configManager.UpdateContext(ctx.Name, fields); err != nil {
+					return fmt.Errorf("failed to update context: %w", err)
+				}
+
+				updated, err := configManager.GetContextByName(ctx.Name)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("Context '%s' updated\n", ctx.Name)
+				printContextDetails(updated)
+				return nil
+			}
+
+			if !cmd.Flags().Changed("cluster") {
+				return fmt.Errorf(`required flag(s) "cluster" not set`)
+			}
+
 			if err := configManager.SetContext(&ctx); err != nil {
 				return fmt.Errorf("failed to save context: %w", err)
 			}
 
 			fmt.Printf("Context '%s' created and set as current context\n", ctx.Name)
-			fmt.Printf("Cluster: %s\n", ctx.Cluster)
-			fmt.Printf("Profile: %s\n", ctx.Profile)
-			fmt.Printf("Region: %s\n", ctx.Region)
+			printContextDetails(&ctx)
 
 			return nil
 		},
@@ -108,7 +174,29 @@ Example:
 	flags.StringVar(&ctx.Cluster, "cluster", "", "ECS cluster name")
 	flags.StringVar(&ctx.Profile, "profile", "default", "AWS profile name")
 	flags.StringVar(&ctx.Region, "region", "us-east-1", "AWS region")
-	cmd.MarkFlagRequired("cluster")
+	flags.StringVar(&ctx.Credentials.Source, "credentials-source", "", "Source of the base credentials: profile, env, process or web-identity (default: profile)")
+	flags.StringVar(&ctx.Credentials.Process, "credential-process", "", "Command printing credentials, for --credentials-source process")
+	flags.StringVar(&ctx.Credentials.WebIdentityTokenFile, "web-identity-token-file", "", "OIDC token file exchanged for --role-arn, for --credentials-source web-identity (default: $AWS_WEB_IDENTITY_TOKEN_FILE)")
+	flags.StringVar(&ctx.RoleArn, "role-arn", "", "IAM role to assume on top of the base credentials, or with the web identity token")
+	flags.StringVar(&ctx.ExternalID, "external-id", "", "External ID to pass when assuming the role")
+	flags.StringVar(&ctx.SessionName, "session-name", "", "Session name to use when assuming the role")
+	flags.DurationVar(&ctx.SessionDuration, "session-duration", 0, "Duration of the assumed role session (e.g., 1h)")
+	flags.StringVar(&ctx.MFASerial, "mfa-serial", "", "ARN or serial number of the MFA device required by the role")
+	flags.StringVar(&ctx.SSO.StartURL, "sso-start-url", "", "IAM Identity Center start URL, to use SSO credentials instead of the profile")
+	flags.StringVar(&ctx.SSO.Region, "sso-region", "", "Region of IAM Identity Center")
+	flags.StringVar(&ctx.SSO.AccountID, "sso-account-id", "", "AWS account ID of the SSO role")
+	flags.StringVar(&ctx.SSO.RoleName, "sso-role-name", "", "Name of the SSO role (permission set)")
+	flags.StringVar(&ctx.SSO.Session, "sso-session", "", "Name of the SSO session whose token cache to share (default: the start URL)")
+	flags.StringVar(&ctx.EndpointURL, "endpoint-url", "", "Endpoint URL used for all AWS services (e.g., LocalStack)")
+	flags.StringVar(&ctx.Endpoints.ECS, "ecs-endpoint-url", "", "Endpoint URL for ECS, overriding --endpoint-url")
+	flags.StringVar(&ctx.Endpoints.Logs, "logs-endpoint-url", "", "Endpoint URL for CloudWatch Logs, overriding --endpoint-url")
+	flags.StringVar(&ctx.Endpoints.SSM, "ssm-endpoint-url", "", "Endpoint URL for SSM, overriding --endpoint-url")
+	flags.IntVar(&ctx.Retry.MaxAttempts, "retry-max-attempts", 0, "Maximum attempts per request, retries included (default: 3)")
+	flags.StringVar(&ctx.Retry.Mode, "retry-mode", "", "Retry mode: standard, or adaptive to also slow down when throttled (default: standard)")
+	flags.Float64Var(&ctx.Retry.RateLimit, "rate-limit", 0, "Maximum AWS requests per second per command, retries included (default: no limit)")
+	flags.IntVar(&ctx.Retry.Burst, "rate-burst", 0, "Requests sent at once before --rate-limit applies (default: one second of requests)")
+	flags.BoolVar(&ctx.Protected, "protected", false, "Require confirmation for commands that change resources in this context")
+	flags.BoolVar(&merge, "merge", false, "Only change the given flags of an existing context, keeping the current context")
 
 	return cmd
 }
@@ -124,6 +212,10 @@ func configGetContextsCmd() *cobra.Command {
 			if err != nil {
 				return fmt.Errorf("failed to list contexts: %w", err)
 			}
+			// A context pinned by --context or ECS_CONTEXT is the active one
+			if name := contextOverrides().Context; name != "" {
+				currentContext = name
+			}
 			printContextHeaders(w, false)
 			for _, ctx := range contexts {
 				printContext(ctx.Name, &ctx, w, false, ctx.Name == currentContext)
@@ -135,22 +227,136 @@ func configGetContextsCmd() *cobra.Command {
 }
 
 func configUseContextCmd() *cobra.Command {
-	return &cobra.Command{
+	var shell bool
+
+	cmd := &cobra.Command{
 		Use:   "use-context NAME",
 		Short: "Set the current context",
 		Long: `Set the current context to use for ECS commands.
 
+With --shell the config file is left untouched and a command pinning the
+context for the current shell session is printed instead. Evaluate it, or
+install the wrapper from 'ecs config shell-init' to have it evaluated for you.
+
 Example:
-  ecs config use-context prod`,
+  ecs config use-context prod
+
+  # Use prod in this terminal only
+  eval "$(ecs config use-context prod --shell)"`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if shell {
+				if _, err := configManager.GetContextByName(args[0]); err != nil {
+					return err
+				}
+				fmt.Println(shellExport(detectShell(), "ECS_CONTEXT", args[0]))
+				return nil
+			}
+
 			if err := configManager.UseContext(args[0]); err != nil {
 				return err
 			}
 			fmt.Printf("Switched to context %q\n", args[0])
+
+			// ECS_CONTEXT takes precedence over current-context, so this
+			// shell keeps using the context it pinned
+			if pinned := os.Getenv("ECS_CONTEXT"); pinned != "" && pinned != args[0] {
+				fmt.Fprintf(os.Stderr, "WARNING: this shell still uses context %q from ECS_CONTEXT, run '%s' to follow current-context\n", pinned, shellUnset(detectShell(), "ECS_CONTEXT"))
+			}
 			return nil
 		},
 	}
+
+	cmd.Flags().BoolVar(&shell, "shell", false, "Print a command that selects the context for the current shell only")
+
+	return cmd
+}
+
+func configShellInitCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:       "shell-init [bash|zsh|fish]",
+		Short:     "Print shell integration for per-shell contexts",
+		ValidArgs: []string{"bash", "zsh", "fish"},
+		Long: `Print a shell function that wraps ecs so that
+'ecs config use-context NAME --shell' switches the context of the current
+shell session only, leaving current-context in the config file as the default
+for every other terminal.
+
+The shell is detected from $SHELL when not given.
+
+Examples:
+  # bash or zsh, in ~/.bashrc or ~/.zshrc
+  eval "$(ecs config shell-init bash)"
+
+  # fish, in ~/.config/fish/config.fish
+  ecs config shell-init fish | source`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			shell := detectShell()
+			if len(args) > 0 {
+				shell = args[0]
+			}
+
+			switch shell {
+			case "bash", "zsh":
+				fmt.Print(posixShellInit)
+			case "fish":
+				fmt.Print(fishShellInit)
+			default:
+				return fmt.Errorf("unsupported shell: %s", shell)
+			}
+			return nil
+		},
+	}
+}
+
+const posixShellInit = `ecs() {
+  if [ "$1" = "config" ] && [ "$2" = "use-context" ]; then
+    for arg in "$@"; do
+      if [ "$arg" = "--shell" ]; then
+        local out
+        out="$(command ecs "$@")" || return
+        eval "$out"
+        echo "Switched to context \"$ECS_CONTEXT\" in this shell"
+        return
+      fi
+    done
+  fi
+  command ecs "$@"
+}
+`
+
+const fishShellInit = `function ecs
+  if test "$argv[1]" = config; and test "$argv[2]" = use-context; and contains -- --shell $argv
+    set -l out (command ecs $argv); or return
+    eval $out
+    echo "Switched to context \"$ECS_CONTEXT\" in this shell"
+    return
+  end
+  command ecs $argv
+end
+`
+
+// detectShell returns the name of the user's shell from $SHELL
+func detectShell() string {
+	return filepath.Base(os.Getenv("SHELL"))
+}
+
+// shellExport returns the command setting an environment variable in shell
+func shellExport(shell, name, value string) string {
+	quoted := "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+	if shell == "fish" {
+		return fmt.Sprintf("set -gx %s %s", name, quoted)
+	}
+	return fmt.Sprintf("export %s=%s", name, quoted)
+}
+
+// shellUnset returns the command removing an environment variable in shell
+func shellUnset(shell, name string) string {
+	if shell == "fish" {
+		return fmt.Sprintf("set -e %s", name)
+	}
+	return fmt.Sprintf("unset %s", name)
 }
 
 func configDeleteContextCmd() *cobra.Command {
@@ -179,15 +385,75 @@ func configCurrentContextCmd() *cobra.Command {
 		Long:  "Display the name and details of the current context in use",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			ctx, err := configManager.GetContext()
+			ctx, err := currentContext()
 			if err != nil {
 				return err
 			}
 
 			fmt.Printf("Current context: %s\n", ctx.Name)
-			fmt.Printf("Cluster: %s\n", ctx.Cluster)
-			fmt.Printf("Profile: %s\n", ctx.Profile)
-			fmt.Printf("Region: %s\n", ctx.Region)
+			printContextDetails(ctx)
+			return nil
+		},
+	}
+}
+
+func configRenameContextCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rename-context OLD_NAME NEW_NAME",
+		Short: "Rename a context",
+		Long: `Rename a context. If it is the current context, current-context follows it.
+
+Example:
+  ecs config rename-context prod prod-us`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := configManager.RenameContext(args[0], args[1]); err != nil {
+				return err
+			}
+			fmt.Printf("Context %q renamed to %q\n", args[0], args[1])
+			return nil
+		},
+	}
+}
+
+func configCopyContextCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "copy-context SOURCE NAME",
+		Short: "Copy a context under a new name",
+		Long: `Copy a context under a new name, e.g. as a starting point for a similar cluster.
+
+Example:
+  ecs config copy-context prod prod-canary
+  ecs config set-context prod-canary --merge --cluster production-canary`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := configManager.CopyContext(args[0], args[1]); err != nil {
+				return err
+			}
+			fmt.Printf("Context %q copied to %q\n", args[0], args[1])
+			return nil
+		},
+	}
+}
+
+func configSetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set KEY VALUE",
+		Short: "Set an individual value in the config file",
+		Long: `Set an individual value in the config file. KEY is a dot-separated path
+such as current-context or contexts.NAME.FIELD, using the field names shown
+by 'ecs config view'. An empty VALUE clears a field.
+
+Examples:
+  ecs config set contexts.prod.region us-west-2
+  ecs config set contexts.prod.endpoints.ecs http://localhost:4566
+  ecs config set current-context prod`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := configManager.Set(args[0], args[1]); err != nil {
+				return err
+			}
+			fmt.Printf("Property %q set\n", args[0])
 			return nil
 		},
 	}
@@ -210,6 +476,73 @@ func configViewCmd() *cobra.Command {
 	}
 }
 
+// printContextDetails prints the settings of a single context
+func printContextDetails(ctx *types.Context) {
+	fmt.Printf("Cluster: %s\n", ctx.Cluster)
+	fmt.Printf("Profile: %s\n", ctx.Profile)
+	fmt.Printf("Region: %s\n", ctx.Region)
+	if ctx.Credentials.Source != "" {
+		fmt.Printf("Credentials Source: %s\n", ctx.Credentials.Source)
+	}
+	if ctx.Credentials.Process != "" {
+		fmt.Printf("Credential Process: %s\n", ctx.Credentials.Process)
+	}
+	if ctx.Credentials.WebIdentityTokenFile != "" {
+		fmt.Printf("Web Identity Token File: %s\n", ctx.Credentials.WebIdentityTokenFile)
+	}
+	if ctx.RoleArn != "" {
+		fmt.Printf("Role ARN: %s\n", ctx.RoleArn)
+	}
+	if ctx.ExternalID != "" {
+		fmt.Printf("External ID: %s\n", ctx.ExternalID)
+	}
+	if ctx.SessionName != "" {
+		fmt.Printf("Session Name: %s\n", ctx.SessionName)
+	}
+	if ctx.SessionDuration > 0 {
+		fmt.Printf("Session Duration: %s\n", ctx.SessionDuration)
+	}
+	if ctx.MFASerial != "" {
+		fmt.Printf("MFA Serial: %s\n", ctx.MFASerial)
+	}
+	if ctx.SSO.StartURL != "" {
+		fmt.Printf("SSO Start URL: %s\n", ctx.SSO.StartURL)
+		fmt.Printf("SSO Region: %s\n", ctx.SSO.Region)
+		fmt.Printf("SSO Account ID: %s\n", ctx.SSO.AccountID)
+		fmt.Printf("SSO Role Name: %s\n", ctx.SSO.RoleName)
+	}
+	if ctx.SSO.Session != "" {
+		fmt.Printf("SSO Session: %s\n", ctx.SSO.Session)
+	}
+	if ctx.EndpointURL != "" {
+		fmt.Printf("Endpoint URL: %s\n", ctx.EndpointURL)
+	}
+	if ctx.Endpoints.ECS != "" {
+		fmt.Printf("ECS Endpoint URL: %s\n", ctx.Endpoints.ECS)
+	}
+	if ctx.Endpoints.Logs != "" {
+		fmt.Printf("Logs Endpoint URL: %s\n", ctx.Endpoints.Logs)
+	}
+	if ctx.Endpoints.SSM != "" {
+		fmt.Printf("SSM Endpoint URL: %s\n", ctx.Endpoints.SSM)
+	}
+	if ctx.Retry.MaxAttempts > 0 {
+		fmt.Printf("Retry Max Attempts: %d\n", ctx.Retry.MaxAttempts)
+	}
+	if ctx.Retry.Mode != "" {
+		fmt.Printf("Retry Mode: %s\n", ctx.Retry.Mode)
+	}
+	if ctx.Retry.RateLimit > 0 {
+		fmt.Printf("Rate Limit: %g/s\n", ctx.Retry.RateLimit)
+	}
+	if ctx.Retry.Burst > 0 {
+		fmt.Printf("Rate Burst: %d\n", ctx.Retry.Burst)
+	}
+	if ctx.Protected {
+		fmt.Println("Protected: true")
+	}
+}
+
 func printContextHeaders(out io.Writer, nameOnly bool) error {
 	columnNames := []string{"CURRENT", "NAME", "CLUSTER", "PROFILE", "REGION"}
 	if nameOnly {
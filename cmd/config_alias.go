@@ -0,0 +1,104 @@
+// cmd/config_alias.go
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+func configAliasCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "alias",
+		Short: "Manage command aliases",
+		Long: `Manage command aliases stored in the config file. An alias is expanded in
+place of the first command word, and any further arguments are appended to
+the expansion. Built-in commands cannot be aliased.
+
+Examples:
+  ecs config alias set prod-logs 'logs --context prod --since 1h -f'
+  ecs prod-logs my-service`,
+	}
+
+	cmd.AddCommand(configAliasSetCmd())
+	cmd.AddCommand(configAliasListCmd())
+	cmd.AddCommand(configAliasDeleteCmd())
+
+	return cmd
+}
+
+func configAliasSetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set NAME COMMAND...",
+		Short: "Create or replace an alias",
+		Long: `Create or replace an alias. COMMAND is quoted as a whole, or given after --
+so that its flags are not read as flags of this command.
+
+Examples:
+  ecs config alias set wide-tasks 'get tasks -o wide'
+  ecs config alias set app-logs -- logs --container app --since 30m`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			if isBuiltinCommand(name) {
+				return fmt.Errorf("'%s' is a built-in command and cannot be aliased", name)
+			}
+
+			expansion := args[1]
+			if len(args) > 2 {
+				expansion = joinArgs(args[1:])
+			}
+			if _, err := splitArgs(expansion); err != nil {
+				return fmt.Errorf("invalid command for alias '%s': %w", name, err)
+			}
+
+			if err := configManager.SetAlias(name, expansion); err != nil {
+				return fmt.Errorf("failed to set alias: %w", err)
+			}
+			fmt.Printf("Alias '%s' set to: %s\n", name, expansion)
+			return nil
+		},
+	}
+}
+
+func configAliasListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List all aliases",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			aliases := configManager.Aliases()
+			names := make([]string, 0, len(aliases))
+			for name := range aliases {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
+			fmt.Fprintln(w, "NAME\tCOMMAND")
+			for _, name := range names {
+				fmt.Fprintf(w, "%s\t%s\n", name, aliases[name])
+			}
+			return w.Flush()
+		},
+	}
+}
+
+func configAliasDeleteCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete NAME",
+		Short: "Delete an alias",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := configManager.DeleteAlias(args[0]); err != nil {
+				return fmt.Errorf("failed to delete alias: %w", err)
+			}
+			fmt.Printf("Alias '%s' deleted\n", args[0])
+			return nil
+		},
+	}
+}
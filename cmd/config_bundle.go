@@ -0,0 +1,160 @@
+// cmd/config_bundle.go
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/yogendratamang48/ecs/pkg/config"
+	"github.com/yogendratamang48/ecs/pkg/types"
+)
+
+func configExportCmd() *cobra.Command {
+	var outputFile string
+	var includePersonal bool
+
+	cmd := &cobra.Command{
+		Use:   "export [CONTEXT...]",
+		Short: "Export contexts to a portable bundle",
+		Long: `Export the given contexts, or all contexts, as a YAML bundle that can be
+shared with a team and loaded with 'ecs config import'.
+
+Settings that only work on your machine are left out unless
+--include-personal is given: the MFA serial, the credential process and the
+web identity token file. Contexts using a credential process fall back to
+their profile.
+
+Examples:
+  # Export every context to stdout
+  ecs config export
+
+  # Export two contexts to a file
+  ecs config export staging prod -f team-contexts.yaml`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bundle, err := configManager.ExportContexts(args...)
+			if err != nil {
+				return fmt.Errorf("failed to export contexts: %w", err)
+			}
+			if !includePersonal {
+				config.StripPersonal(bundle)
+			}
+
+			data, err := config.MarshalBundle(bundle)
+			if err != nil {
+				return fmt.Errorf("failed to marshal bundle: %w", err)
+			}
+
+			if outputFile == "" || outputFile == "-" {
+				fmt.Print(string(data))
+				return nil
+			}
+
+			if err := os.WriteFile(outputFile, data, 0644); err != nil {
+				return fmt.Errorf("failed to write bundle: %w", err)
+			}
+			fmt.Printf("Exported %d context(s) to %s\n", len(bundle.Contexts), outputFile)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&outputFile, "file", "f", "", "Write the bundle to a file instead of stdout")
+	cmd.Flags().BoolVar(&includePersonal, "include-personal", false, "Keep the MFA serial, credential process and web identity token file")
+
+	return cmd
+}
+
+func configImportCmd() *cobra.Command {
+	var onConflict string
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "import FILE",
+		Short: "Import contexts from a bundle",
+		Long: `Import the contexts of a bundle created by 'ecs config export'. The output of
+'ecs config view' and whole config files can be imported as well. Use - to
+read from stdin.
+
+When a context with the same name already exists, --on-conflict decides
+whether the imported one is skipped, overwrites it, or is renamed.
+
+Contexts that run a credential process, read a web identity token file or
+override an endpoint are listed before importing and need to be confirmed, or
+--yes given, since the process runs on this machine and the endpoint gets your
+signed requests whenever the context is used.
+
+Examples:
+  # Import a bundle, keeping existing contexts
+  ecs config import team-contexts.yaml
+
+  # Import a bundle, replacing existing contexts
+  ecs config import team-contexts.yaml --on-conflict overwrite`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			strategy, err := config.ParseConflictStrategy(onConflict)
+			if err != nil {
+				return err
+			}
+
+			var data []byte
+			if args[0] == "-" {
+				data, err = io.ReadAll(os.Stdin)
+			} else {
+				data, err = os.ReadFile(args[0])
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read bundle: %w", err)
+			}
+
+			bundle, err := config.UnmarshalBundle(data)
+			if err != nil {
+				return err
+			}
+			if err := confirmSensitiveSettings(bundle, yes); err != nil {
+				return err
+			}
+
+			results, err := configManager.ImportContexts(bundle, strategy)
+			if err != nil {
+				return fmt.Errorf("failed to import contexts: %w", err)
+			}
+
+			for _, result := range results {
+				switch result.Action {
+				case "renamed":
+					fmt.Printf("Context %q imported as %q\n", result.Name, result.SavedAs)
+				default:
+					fmt.Printf("Context %q %s\n", result.Name, result.Action)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&onConflict, "on-conflict", string(config.ConflictSkip), "What to do with existing contexts: skip|overwrite|rename")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Import contexts with a credential process, token file or endpoint without prompting")
+
+	return cmd
+}
+
+// confirmSensitiveSettings lists the imported contexts that run a command or
+// read a file to get credentials, or that override an endpoint, and asks
+// before importing them
+func confirmSensitiveSettings(bundle *types.ContextBundle, yes bool) error {
+	found := false
+	for _, ctx := range bundle.Contexts {
+		for _, setting := range config.SensitiveSettings(ctx) {
+			fmt.Fprintf(os.Stderr, "Context %q %s\n", ctx.Name, setting)
+			found = true
+		}
+	}
+	if !found || yes {
+		return nil
+	}
+
+	if !confirm("Import contexts with these settings?") {
+		return fmt.Errorf("import aborted, pass --yes to import contexts with a credential process, token file or endpoint")
+	}
+	return nil
+}
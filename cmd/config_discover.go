@@ -0,0 +1,143 @@
+// cmd/config_discover.go
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yogendratamang48/ecs/pkg/aws"
+	"github.com/yogendratamang48/ecs/pkg/types"
+)
+
+func configDiscoverCmd() *cobra.Command {
+	var (
+		profiles     []string
+		regions      []string
+		nameTemplate string
+		yes          bool
+		overwrite    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "discover",
+		Short: "Create contexts for the clusters reachable from your AWS profiles",
+		Long: `Discover ECS clusters for the profiles in the shared AWS config and offer to
+create a context for each of them.
+
+Clusters are listed in the regions given with --regions, or in each profile's
+configured region. Context names are built from --name-template, where
+{profile}, {cluster} and {region} are replaced.
+
+Examples:
+  # Discover clusters for every profile in its default region
+  ecs config discover
+
+  # Discover clusters in two regions for selected profiles, without prompting
+  ecs config discover --profiles dev,prod --regions us-east-1,eu-west-1 --yes
+
+  # Use a custom naming scheme
+  ecs config discover --name-template "{cluster}@{region}"`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(profiles) == 0 {
+				var err error
+				profiles, err = aws.SharedConfigProfiles()
+				if err != nil {
+					return fmt.Errorf("failed to read AWS profiles: %w", err)
+				}
+				if len(profiles) == 0 {
+					return fmt.Errorf("no profiles found in the shared AWS config")
+				}
+			}
+
+			var discovered []*types.Context
+			for _, profile := range profiles {
+				profileRegions := regions
+				if len(profileRegions) == 0 {
+					region := aws.ProfileRegion(cmd.Context(), profile)
+					if region == "" {
+						fmt.Printf("Skipping profile %s: no region configured, use --regions\n", profile)
+						continue
+					}
+					profileRegions = []string{region}
+				}
+
+				for _, region := range profileRegions {
+					clusters, err := listClusters(cmd.Context(), profile, region)
+					if err != nil {
+						fmt.Printf("Skipping profile %s in %s: %v\n", profile, region, err)
+						continue
+					}
+					for _, cluster := range clusters {
+						discovered = append(discovered, &types.Context{
+							Name:    expandNameTemplate(nameTemplate, profile, cluster, region),
+							Cluster: cluster,
+							Profile: profile,
+							Region:  region,
+						})
+					}
+				}
+			}
+
+			if len(discovered) == 0 {
+				fmt.Println("No clusters found")
+				return nil
+			}
+
+			created := 0
+			for _, ctx := range discovered {
+				if _, err := configManager.GetContextByName(ctx.Name); err == nil && !overwrite {
+					fmt.Printf("Context %q already exists, skipping (use --overwrite to replace it)\n", ctx.Name)
+					continue
+				}
+
+				question := fmt.Sprintf("Create context %q (cluster %s, profile %s, region %s)?", ctx.Name, ctx.Cluster, ctx.Profile, ctx.Region)
+				if !yes && !confirm(question) {
+					continue
+				}
+
+				if err := configManager.SaveContext(ctx); err != nil {
+					return fmt.Errorf("failed to save context: %w", err)
+				}
+				fmt.Printf("Context %q created\n", ctx.Name)
+				created++
+			}
+
+			fmt.Printf("%d context(s) created\n", created)
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringSliceVar(&profiles, "profiles", nil, "AWS profiles to search (default: all profiles in the shared config)")
+	flags.StringSliceVar(&regions, "regions", nil, "Regions to search (default: each profile's region)")
+	flags.StringVar(&nameTemplate, "name-template", "{profile}-{cluster}", "Template for context names, using {profile}, {cluster} and {region}")
+	flags.BoolVarP(&yes, "yes", "y", false, "Create all discovered contexts without prompting")
+	flags.BoolVar(&overwrite, "overwrite", false, "Replace existing contexts with the same name")
+
+	return cmd
+}
+
+// listClusters returns the clusters reachable with a profile in a region
+func listClusters(ctx context.Context, profile, region string) ([]string, error) {
+	client, err := newECSClient(&types.Context{
+		Name:    profile,
+		Profile: profile,
+		Region:  region,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return client.ListClusters(ctx)
+}
+
+// expandNameTemplate builds a context name from a discovery naming template
+func expandNameTemplate(template, profile, cluster, region string) string {
+	return strings.NewReplacer(
+		"{profile}", profile,
+		"{cluster}", cluster,
+		"{region}", region,
+	).Replace(template)
+}
@@ -0,0 +1,130 @@
+// cmd/config_doctor.go
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/yogendratamang48/ecs/pkg/aws"
+)
+
+func configDoctorCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor [NAME]",
+		Short: "Check that a context is ready to use",
+		Long: `Check the active or named context end-to-end and print the result of each check:
+  * the region is a valid AWS region
+  * credentials resolve and identify a principal
+  * the cluster exists and is ACTIVE
+  * the IAM actions used by ecs are allowed
+  * session-manager-plugin is installed with a supported version
+
+Examples:
+  # Check the current context
+  ecs config doctor
+
+  # Check the "prod" context
+  ecs config doctor prod`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o := contextOverrides()
+			if len(args) > 0 {
+				o.Context = args[0]
+			}
+			ctx, err := configManager.ResolveContext(o)
+			if err != nil {
+				return fmt.Errorf("failed to get context: %w", err)
+			}
+
+			fmt.Printf("Context: %s (cluster %s, region %s)\n\n", ctx.Name, ctx.Cluster, ctx.Region)
+
+			r := &doctorReport{w: tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)}
+
+			if aws.ValidRegion(ctx.Region) {
+				r.pass("Region", ctx.Region)
+			} else {
+				r.fail("Region", fmt.Errorf("%q is not a valid AWS region", ctx.Region))
+			}
+
+			apiCtx := cmd.Context()
+			session, err := newSession(ctx)
+			if err == nil {
+				var source string
+				if source, err = session.Credentials(apiCtx); err == nil {
+					r.pass("Credentials", "resolved from "+source)
+				}
+			}
+			if err != nil {
+				r.fail("Credentials", err)
+				r.skip("Identity", "credentials did not resolve")
+				r.skip("Cluster", "credentials did not resolve")
+				r.skip("IAM permissions", "credentials did not resolve")
+			} else {
+				callerArn, err := session.CallerIdentity(apiCtx)
+				if err != nil {
+					r.fail("Identity", err)
+				} else {
+					r.pass("Identity", callerArn)
+				}
+
+				status, err := session.ECS().GetClusterStatus(apiCtx)
+				switch {
+				case err != nil:
+					r.fail("Cluster", err)
+				case status != "ACTIVE":
+					r.fail("Cluster", fmt.Errorf("cluster %s is %s", ctx.Cluster, status))
+				default:
+					r.pass("Cluster", ctx.Cluster+" is ACTIVE")
+				}
+
+				if callerArn == "" {
+					r.skip("IAM permissions", "caller identity is unknown")
+				} else if denied, err := session.SimulateActions(apiCtx, callerArn, aws.RequiredActions); err != nil {
+					r.skip("IAM permissions", fmt.Sprintf("could not simulate policies: %v", err))
+				} else if len(denied) > 0 {
+					r.fail("IAM permissions", fmt.Errorf("not allowed: %s", strings.Join(denied, ", ")))
+				} else {
+					r.pass("IAM permissions", fmt.Sprintf("%d actions allowed", len(aws.RequiredActions)))
+				}
+			}
+
+			version, err := aws.SessionManagerPluginVersion()
+			switch {
+			case err != nil:
+				r.fail("Session Manager plugin", err)
+			case aws.CompareVersions(version, aws.MinSessionManagerPluginVersion) < 0:
+				r.fail("Session Manager plugin", fmt.Errorf("version %s is older than %s", version, aws.MinSessionManagerPluginVersion))
+			default:
+				r.pass("Session Manager plugin", "version "+version)
+			}
+
+			r.w.Flush()
+			if r.failed > 0 {
+				return fmt.Errorf("%d check(s) failed", r.failed)
+			}
+			return nil
+		},
+	}
+}
+
+// doctorReport prints the outcome of each doctor check
+type doctorReport struct {
+	w      *tabwriter.Writer
+	failed int
+}
+
+func (r *doctorReport) pass(check, detail string) {
+	fmt.Fprintf(r.w, "[PASS]\t%s\t%s\n", check, detail)
+}
+
+func (r *doctorReport) fail(check string, err error) {
+	r.failed++
+	fmt.Fprintf(r.w, "[FAIL]\t%s\t%v\n", check, err)
+}
+
+func (r *doctorReport) skip(check, reason string) {
+	fmt.Fprintf(r.w, "[SKIP]\t%s\t%s\n", check, reason)
+}
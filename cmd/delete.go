@@ -2,11 +2,9 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
 
 	"github.com/spf13/cobra"
-	"github.com/yogendratamang48/ecs/pkg/aws"
 )
 
 func deleteCmd() *cobra.Command {
@@ -32,7 +30,9 @@ Examples:
 }
 
 func deleteTaskCmd() *cobra.Command {
-	return &cobra.Command{
+	var yes bool
+
+	cmd := &cobra.Command{
 		Use:   "task TASK_ID",
 		Short: "Delete (stop) a task",
 		Long:  `Delete (stop) a specific task from the ECS cluster.`,
@@ -41,19 +41,23 @@ func deleteTaskCmd() *cobra.Command {
 			taskId := args[0]
 
 			// Get current context
-			ctx, err := configManager.GetContext()
+			ctx, err := currentContext()
 			if err != nil {
 				return fmt.Errorf("failed to get current context: %w", err)
 			}
 
+			if err := confirmProtected(ctx, fmt.Sprintf("stop task %s", taskId), yes); err != nil {
+				return err
+			}
+
 			// Create ECS client
-			client, err := aws.NewECSClient(ctx)
+			client, err := newECSClient(ctx)
 			if err != nil {
 				return fmt.Errorf("failed to create ECS client: %w", err)
 			}
 
 			// Stop the task
-			if err := client.StopTask(context.Background(), taskId); err != nil {
+			if err := client.StopTask(cmd.Context(), taskId); err != nil {
 				return fmt.Errorf("failed to stop task: %w", err)
 			}
 
@@ -61,4 +65,8 @@ func deleteTaskCmd() *cobra.Command {
 			return nil
 		},
 	}
+
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip the confirmation required by protected contexts")
+
+	return cmd
 }
@@ -2,13 +2,11 @@
 package cmd
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/spf13/cobra"
-	"github.com/yogendratamang48/ecs/pkg/aws"
 	"gopkg.in/yaml.v2"
 )
 
@@ -57,13 +55,16 @@ Examples:
 
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Get current context
-			ctx, err := configManager.GetContext()
+			ctx, err := currentContext()
 			if err != nil {
 				return fmt.Errorf("failed to get current context: %w", err)
 			}
+			if !cmd.Flags().Changed("output") {
+				outputFormat = defaultOutput(ctx, "json", "yaml")
+			}
 
 			// Create ECS client
-			client, err := aws.NewECSClient(ctx)
+			client, err := newECSClient(ctx)
 			if err != nil {
 				return fmt.Errorf("failed to create ECS client: %w", err)
 			}
@@ -73,7 +74,7 @@ Examples:
 				serviceNames = []string{args[0]}
 			} else {
 				// If no service name provided, get all services
-				services, err := client.ListServices(context.Background())
+				services, err := client.ListServices(cmd.Context())
 				if err != nil {
 					return fmt.Errorf("failed to list services: %w", err)
 				}
@@ -83,7 +84,7 @@ Examples:
 			}
 
 			// Get detailed service information
-			services, err := client.DescribeServices(context.Background(), serviceNames)
+			services, err := client.DescribeServices(cmd.Context(), serviceNames)
 			if err != nil {
 				return fmt.Errorf("failed to describe services: %w", err)
 			}
@@ -140,7 +141,11 @@ Examples:
 
 					if len(svc.Events) > 0 {
 						fmt.Println("\nRecent Events:")
-						for _, event := range svc.Events[:5] { // Show only last 5 events
+						events := svc.Events
+						if len(events) > 5 {
+							events = events[:5] // Show only last 5 events
+						}
+						for _, event := range events {
 							fmt.Printf("  %s: %s\n",
 								event.CreatedAt.Format(time.RFC3339),
 								event.Message)
@@ -180,13 +185,16 @@ Examples:
 
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Get current context
-			ctx, err := configManager.GetContext()
+			ctx, err := currentContext()
 			if err != nil {
 				return fmt.Errorf("failed to get current context: %w", err)
 			}
+			if !cmd.Flags().Changed("output") {
+				outputFormat = defaultOutput(ctx, "json", "yaml")
+			}
 
 			// Create ECS client
-			client, err := aws.NewECSClient(ctx)
+			client, err := newECSClient(ctx)
 			if err != nil {
 				return fmt.Errorf("failed to create ECS client: %w", err)
 			}
@@ -196,7 +204,7 @@ Examples:
 				taskIds = []string{args[0]}
 			} else {
 				// If no task ID provided, get all tasks
-				tasks, err := client.ListTasks(context.Background())
+				tasks, err := client.ListTasks(cmd.Context())
 				if err != nil {
 					return fmt.Errorf("failed to list tasks: %w", err)
 				}
@@ -206,7 +214,7 @@ Examples:
 			}
 
 			// Get detailed task information
-			tasks, err := client.DescribeTasks(context.Background(), taskIds)
+			tasks, err := client.DescribeTasks(cmd.Context(), taskIds)
 			if err != nil {
 				return fmt.Errorf("failed to describe tasks: %w", err)
 			}
@@ -2,12 +2,10 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
 	"strings"
 
 	"github.com/spf13/cobra"
-	"github.com/yogendratamang48/ecs/pkg/aws"
 )
 
 func execCmd() *cobra.Command {
@@ -18,7 +16,8 @@ func execCmd() *cobra.Command {
 		Short: "Execute a command in a running container",
 		Long: `Execute a command in a running container using AWS ECS execute-command.
 
-The container name will be auto-detected if not specified with the -c flag.
+The container name is taken from the -c flag, then from the exec.container
+default in the config file, and is auto-detected otherwise.
 
 Note: AWS ECS execute-command only supports interactive mode.
 
@@ -36,13 +35,13 @@ Examples:
 			taskID := args[0]
 
 			// Get current context
-			ctx, err := configManager.GetContext()
+			ctx, err := currentContext()
 			if err != nil {
 				return fmt.Errorf("failed to get current context: %w", err)
 			}
 
 			// Create ECS client
-			client, err := aws.NewECSClient(ctx)
+			client, err := newECSClient(ctx)
 			if err != nil {
 				return fmt.Errorf("failed to create ECS client: %w", err)
 			}
@@ -70,10 +69,14 @@ Examples:
 				return fmt.Errorf("error: you must specify at least one command for the container")
 			}
 
-			// If container name is not specified, try to detect it
+			// If container name is not specified, use the configured default
+			// or try to detect it
 			containerName := container
 			if containerName == "" {
-				detectedContainer, err := client.GetContainerNameForTask(context.Background(), taskID)
+				containerName = configManager.Defaults(ctx).Exec.Container
+			}
+			if containerName == "" {
+				detectedContainer, err := client.GetContainerNameForTask(cmd.Context(), taskID)
 				if err != nil {
 					return fmt.Errorf("failed to detect container name: %w", err)
 				}
@@ -82,7 +85,7 @@ Examples:
 			}
 
 			// Execute the command in interactive mode
-			if err := client.ExecuteCommand(context.Background(), taskID, true, containerName, command); err != nil {
+			if err := client.ExecuteCommand(cmd.Context(), taskID, true, containerName, command); err != nil {
 				return fmt.Errorf("failed to execute command: %w", err)
 			}
 
@@ -91,7 +94,7 @@ Examples:
 	}
 
 	// Add container flag
-	cmd.Flags().StringVarP(&container, "container", "c", "", "Container name (will be auto-detected if not specified)")
+	cmd.Flags().StringVarP(&container, "container", "c", "", "Container name (defaults to exec.container in the config, else auto-detected)")
 
 	return cmd
 }
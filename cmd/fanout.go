@@ -0,0 +1,103 @@
+// cmd/fanout.go
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"github.com/yogendratamang48/ecs/pkg/types"
+)
+
+// contextSelection holds the flags of commands that can query several
+// contexts at once
+type contextSelection struct {
+	all   bool
+	names []string
+}
+
+func (s *contextSelection) addFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&s.all, "all-contexts", false, "Query every configured context")
+	cmd.Flags().StringSliceVar(&s.names, "contexts", nil, "Query the given contexts (e.g., a,b,c)")
+	cmd.MarkFlagsMutuallyExclusive("all-contexts", "contexts")
+}
+
+// contexts returns the selected contexts, or the current context alone when
+// no selection was made. multi reports whether a selection was made. The
+// --region and --profile overrides apply to every selected context, while a
+// context pinned by ECS_CONTEXT is replaced by the selection.
+func (s *contextSelection) contexts() (contexts []*types.Context, multi bool, err error) {
+	if !s.all && len(s.names) == 0 {
+		ctx, err := currentContext()
+		if err != nil {
+			return nil, false, err
+		}
+		return []*types.Context{ctx}, false, nil
+	}
+
+	o := contextOverrides()
+	if overrides.Context != "" || o.Cluster != "" {
+		return nil, true, fmt.Errorf("--context, --cluster and ECS_CLUSTER cannot be combined with --contexts or --all-contexts")
+	}
+
+	names := s.names
+	if s.all {
+		list, _, err := configManager.ListContexts()
+		if err != nil {
+			return nil, true, err
+		}
+		names = nil
+		for _, ctx := range list {
+			names = append(names, ctx.Name)
+		}
+		if len(names) == 0 {
+			return nil, true, fmt.Errorf("no contexts configured")
+		}
+	}
+
+	for _, name := range names {
+		o.Context = name
+		ctx, err := configManager.ResolveContext(o)
+		if err != nil {
+			return nil, true, err
+		}
+		contexts = append(contexts, ctx)
+	}
+	return contexts, true, nil
+}
+
+// forEachContext calls fn for every context concurrently. The errors are
+// joined, prefixed with the name of their context when there are several.
+func forEachContext(contexts []*types.Context, fn func(i int, ctx *types.Context) error) error {
+	errs := make([]error, len(contexts))
+
+	var wg sync.WaitGroup
+	for i, ctx := range contexts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = fn(i, ctx)
+		}()
+	}
+	wg.Wait()
+
+	if len(contexts) == 1 {
+		return errs[0]
+	}
+	for i, err := range errs {
+		if err != nil {
+			errs[i] = fmt.Errorf("context '%s': %w", contexts[i].Name, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// contextColumn prepends the context column to a table row or header when
+// several contexts are shown
+func contextColumn(multi bool, context string, row []string) []string {
+	if !multi {
+		return row
+	}
+	return append([]string{context}, row...)
+}
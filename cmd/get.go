@@ -2,13 +2,12 @@
 package cmd
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/spf13/cobra"
-	"github.com/yogendratamang48/ecs/pkg/aws"
+	"github.com/yogendratamang48/ecs/pkg/types"
 	"github.com/yogendratamang48/ecs/pkg/utils"
 	"gopkg.in/yaml.v2"
 )
@@ -43,31 +42,64 @@ Examples:
 }
 
 func getServicesCmd() *cobra.Command {
-	var outputFormat string
+	var (
+		outputFormat string
+		selection    contextSelection
+	)
 
 	cmd := &cobra.Command{
 		Use:     "services",
 		Aliases: []string{"svc", "svc", "service", "svcs"},
 		Short:   "List services",
-		Long:    `Display all services in the current ECS cluster context.`,
+		Long: `Display all services in the current ECS cluster context.
+
+Examples:
+  # Compare a service across regional clusters
+  ecs get services --contexts prod-us,prod-eu,prod-ap
+
+  # List the services of every context
+  ecs get services --all-contexts -o json`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			// Get current context
-			ctx, err := configManager.GetContext()
+			// Get the current context, or the contexts to query
+			contexts, multi, err := selection.contexts()
 			if err != nil {
 				return fmt.Errorf("failed to get current context: %w", err)
 			}
+			if !cmd.Flags().Changed("output") {
+				outputFormat = defaultOutput(defaultsContext(contexts, multi), "json", "yaml")
+			}
 
-			// Create ECS client
-			client, err := aws.NewECSClient(ctx)
-			if err != nil {
-				return fmt.Errorf("failed to create ECS client: %w", err)
+			// Get services from every context, keeping the order of contexts
+			results := make([][]*types.Service, len(contexts))
+			listErr := forEachContext(contexts, func(i int, ctx *types.Context) error {
+				client, err := newECSClient(ctx)
+				if err != nil {
+					return fmt.Errorf("failed to create ECS client: %w", err)
+				}
+
+				services, err := client.ListServices(cmd.Context())
+				if err != nil {
+					return fmt.Errorf("failed to list services: %w", err)
+				}
+				if multi {
+					for _, svc := range services {
+						svc.Context = ctx.Name
+					}
+				}
+				results[i] = services
+				return nil
+			})
+			// A single context has nothing to show, while the contexts that
+			// could be queried are shown before reporting the others
+			if !multi && listErr != nil {
+				return listErr
 			}
 
-			// Get services
-			services, err := client.ListServices(context.Background())
-			if err != nil {
-				return fmt.Errorf("failed to list services: %w", err)
+			var services []*types.Service
+			for _, r := range results {
+				services = append(services, r...)
 			}
+
 			switch outputFormat {
 			case "json":
 				data, err := json.MarshalIndent(services, "", "  ")
@@ -75,14 +107,14 @@ func getServicesCmd() *cobra.Command {
 					return fmt.Errorf("failed to marshal to JSON: %w", err)
 				}
 				fmt.Println(string(data))
-				return nil
+				return listErr
 			case "yaml":
 				data, err := yaml.Marshal(services)
 				if err != nil {
 					return fmt.Errorf("failed to marshal to YAML: %w", err)
 				}
 				fmt.Println(string(data))
-				return nil
+				return listErr
 			case "":
 				// Display services
 				headers := []string{
@@ -93,7 +125,7 @@ func getServicesCmd() *cobra.Command {
 					"PENDING",
 					"AGE",
 				}
-				table := utils.NewTableFormatter(headers)
+				table := utils.NewTableFormatter(contextColumn(multi, "CONTEXT", headers))
 
 				for _, svc := range services {
 					age := time.Since(svc.CreatedAt).Round(time.Second)
@@ -106,11 +138,11 @@ func getServicesCmd() *cobra.Command {
 						fmt.Sprintf("%d", svc.PendingCount),
 						formatAge(age),
 					}
-					table.AppendRow(row)
+					table.AppendRow(contextColumn(multi, svc.Context, row))
 				}
 
 				table.Render()
-				return nil
+				return listErr
 			default:
 				return fmt.Errorf("unsupported output format: %s", outputFormat)
 			}
@@ -118,35 +150,68 @@ func getServicesCmd() *cobra.Command {
 	}
 
 	// Add flags
-	cmd.Flags().StringVarP(&outputFormat, "output", "o", "", "Output format (json|yaml|wide)")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "", "Output format (json|yaml)")
+	selection.addFlags(cmd)
 
 	return cmd
 }
 
 func getTasksCmd() *cobra.Command {
-	var outputFormat string
+	var (
+		outputFormat string
+		selection    contextSelection
+	)
 
 	cmd := &cobra.Command{
 		Use:   "tasks",
 		Short: "List tasks",
-		Long:  `Display all tasks in the current ECS cluster context.`,
+		Long: `Display all tasks in the current ECS cluster context.
+
+Examples:
+  # List the tasks of several contexts in one table
+  ecs get tasks --contexts prod-us,prod-eu -o wide
+
+  # List the tasks of every context
+  ecs get tasks --all-contexts`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			// Get current context
-			ctx, err := configManager.GetContext()
+			// Get the current context, or the contexts to query
+			contexts, multi, err := selection.contexts()
 			if err != nil {
 				return fmt.Errorf("failed to get current context: %w", err)
 			}
+			if !cmd.Flags().Changed("output") {
+				outputFormat = defaultOutput(defaultsContext(contexts, multi), "json", "yaml", "wide")
+			}
 
-			// Create ECS client
-			client, err := aws.NewECSClient(ctx)
-			if err != nil {
-				return fmt.Errorf("failed to create ECS client: %w", err)
+			// Get tasks from every context, keeping the order of contexts
+			results := make([][]*types.Task, len(contexts))
+			listErr := forEachContext(contexts, func(i int, ctx *types.Context) error {
+				client, err := newECSClient(ctx)
+				if err != nil {
+					return fmt.Errorf("failed to create ECS client: %w", err)
+				}
+
+				tasks, err := client.ListTasks(cmd.Context())
+				if err != nil {
+					return fmt.Errorf("failed to list tasks: %w", err)
+				}
+				if multi {
+					for _, task := range tasks {
+						task.Context = ctx.Name
+					}
+				}
+				results[i] = tasks
+				return nil
+			})
+			// A single context has nothing to show, while the contexts that
+			// could be queried are shown before reporting the others
+			if !multi && listErr != nil {
+				return listErr
 			}
 
-			// Get tasks
-			tasks, err := client.ListTasks(context.Background())
-			if err != nil {
-				return fmt.Errorf("failed to list tasks: %w", err)
+			var tasks []*types.Task
+			for _, r := range results {
+				tasks = append(tasks, r...)
 			}
 
 			// Handle different output formats
@@ -163,7 +228,7 @@ func getTasksCmd() *cobra.Command {
 					"LAUNCH TYPE",
 					"CAPACITY PROVIDER",
 				}
-				table := utils.NewTableFormatter(headers)
+				table := utils.NewTableFormatter(contextColumn(multi, "CONTEXT", headers))
 				for _, task := range tasks {
 					age := time.Since(task.CreatedAt).Round(time.Second)
 					started := "-"
@@ -182,18 +247,18 @@ func getTasksCmd() *cobra.Command {
 						task.LaunchType,
 						task.CapacityProvider,
 					}
-					table.AppendRow(row)
+					table.AppendRow(contextColumn(multi, task.Context, row))
 				}
 
 				table.Render()
-				return nil
+				return listErr
 			case "json":
 				data, err := json.MarshalIndent(tasks, "", "  ")
 				if err != nil {
 					return fmt.Errorf("failed to marshal to JSON: %w", err)
 				}
 				fmt.Println(string(data))
-				return nil
+				return listErr
 
 			case "yaml":
 				data, err := yaml.Marshal(tasks)
@@ -201,7 +266,7 @@ func getTasksCmd() *cobra.Command {
 					return fmt.Errorf("failed to marshal to YAML: %w", err)
 				}
 				fmt.Println(string(data))
-				return nil
+				return listErr
 
 			case "":
 				// Default table output
@@ -213,7 +278,7 @@ func getTasksCmd() *cobra.Command {
 					"AGE",
 				}
 
-				table := utils.NewTableFormatter(headers)
+				table := utils.NewTableFormatter(contextColumn(multi, "CONTEXT", headers))
 
 				for _, task := range tasks {
 					age := time.Since(task.CreatedAt).Round(time.Second)
@@ -229,11 +294,11 @@ func getTasksCmd() *cobra.Command {
 						started,
 						formatAge(age),
 					}
-					table.AppendRow(row)
+					table.AppendRow(contextColumn(multi, task.Context, row))
 				}
 
 				table.Render()
-				return nil
+				return listErr
 
 			default:
 				return fmt.Errorf("unsupported output format: %s", outputFormat)
@@ -243,6 +308,7 @@ func getTasksCmd() *cobra.Command {
 
 	// Add flags
 	cmd.Flags().StringVarP(&outputFormat, "output", "o", "", "Output format (json|yaml|wide)")
+	selection.addFlags(cmd)
 
 	return cmd
 }
@@ -260,3 +326,25 @@ func formatAge(age time.Duration) string {
 	}
 	return fmt.Sprintf("%dy", int(age.Hours()/(24*365)))
 }
+
+// defaultsContext returns the context whose defaults apply to a command, or
+// nil for the global defaults when several contexts are queried
+func defaultsContext(contexts []*types.Context, multi bool) *types.Context {
+	if multi {
+		return nil
+	}
+	return contexts[0]
+}
+
+// defaultOutput returns the configured default output format when it is one
+// of the formats a command supports, and the command's table otherwise, so
+// that e.g. a wide default doesn't break commands without a wide table
+func defaultOutput(ctx *types.Context, supported ...string) string {
+	output := configManager.Defaults(ctx).Output
+	for _, format := range supported {
+		if output == format {
+			return output
+		}
+	}
+	return ""
+}
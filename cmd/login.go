@@ -0,0 +1,57 @@
+// cmd/login.go
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yogendratamang48/ecs/pkg/aws"
+	"github.com/yogendratamang48/ecs/pkg/types"
+)
+
+func loginCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "login [CONTEXT]",
+		Short: "Sign in to IAM Identity Center for a context",
+		Long: `Sign in to IAM Identity Center (AWS SSO) with the SSO settings of the given
+context, or of the current context. A URL and a code are shown to confirm in
+a browser, after which the access token is cached where the AWS CLI finds it
+too, and refreshed automatically while possible.
+
+Examples:
+  # Configure a context using SSO and sign in
+  ecs config set-context prod --cluster production-cluster --region us-west-2 \
+    --sso-start-url https://my-org.awsapps.com/start --sso-region us-east-1 \
+    --sso-account-id 123456789012 --sso-role-name ECSOperator
+  ecs login prod`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var ctx *types.Context
+			var err error
+			if len(args) == 1 {
+				ctx, err = configManager.GetContextByName(args[0])
+			} else {
+				ctx, err = currentContext()
+			}
+			if err != nil {
+				return fmt.Errorf("failed to get context: %w", err)
+			}
+			if ctx.SSO.StartURL == "" {
+				return fmt.Errorf("context '%s' has no SSO settings, see 'ecs config set-context --help'", ctx.Name)
+			}
+
+			expires, err := aws.SSOLogin(cmd.Context(), ctx.SSO, func(url, code string) {
+				fmt.Fprintf(os.Stderr, "Open the following URL in a browser and confirm the code %s:\n\n  %s\n\n", code, url)
+				fmt.Fprintln(os.Stderr, "Waiting for authorization...")
+			})
+			if err != nil {
+				return fmt.Errorf("failed to sign in: %w", err)
+			}
+
+			fmt.Printf("Signed in to %s until %s\n", ctx.SSO.StartURL, expires.Local().Format(time.RFC1123))
+			return nil
+		},
+	}
+}
@@ -2,12 +2,10 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
 	"time"
 
 	"github.com/spf13/cobra"
-	"github.com/yogendratamang48/ecs/pkg/aws"
 )
 
 func logsCmd() *cobra.Command {
@@ -33,26 +31,40 @@ Examples:
   ecs logs 1234567890-abcd --since=1h
 
   # View logs from a specific container
-  ecs logs 1234567890-abcd --container=nginx`,
+  ecs logs 1234567890-abcd --container=nginx
+
+The --since and --container defaults can be changed in the config file, for
+all contexts or a single one:
+  ecs config set defaults.logs.since 1h
+  ecs config set contexts.prod.defaults.logs.container app`,
 
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			taskID := args[0]
 
 			// Get current context
-			ctx, err := configManager.GetContext()
+			ctx, err := currentContext()
 			if err != nil {
 				return fmt.Errorf("failed to get current context: %w", err)
 			}
 
+			// Flags that weren't given fall back to the config defaults
+			defaults := configManager.Defaults(ctx).Logs
+			if !cmd.Flags().Changed("since") && defaults.Since != 0 {
+				since = defaults.Since
+			}
+			if !cmd.Flags().Changed("container") && defaults.Container != "" {
+				container = defaults.Container
+			}
+
 			// Create ECS client
-			client, err := aws.NewECSClient(ctx)
+			client, err := newECSClient(ctx)
 			if err != nil {
 				return fmt.Errorf("failed to create ECS client: %w", err)
 			}
 
 			// Get logs
-			logChan, err := client.GetTaskLogs(context.Background(), taskID, follow, since, container)
+			logChan, err := client.GetTaskLogs(cmd.Context(), taskID, follow, since, container)
 			if err != nil {
 				return fmt.Errorf("failed to get logs: %w", err)
 			}
@@ -0,0 +1,58 @@
+// cmd/prompt.go
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/yogendratamang48/ecs/pkg/types"
+)
+
+var stdin = bufio.NewReader(os.Stdin)
+
+// prompt prints a question and returns the trimmed answer read from stdin
+func prompt(question string) (string, error) {
+	fmt.Fprint(os.Stderr, question)
+	answer, err := stdin.ReadString('\n')
+	if err != nil && answer == "" {
+		return "", err
+	}
+	return strings.TrimSpace(answer), nil
+}
+
+// confirm asks a yes/no question, defaulting to no
+func confirm(question string) bool {
+	answer, err := prompt(question + " [y/N] ")
+	if err != nil {
+		return false
+	}
+	answer = strings.ToLower(answer)
+	return answer == "y" || answer == "yes"
+}
+
+// confirmProtected guards a command that changes resources. In a protected
+// context it prints a warning naming the context and cluster, and requires
+// the cluster name to be typed unless --yes was given.
+func confirmProtected(ctx *types.Context, action string, yes bool) error {
+	if !ctx.Protected || yes {
+		return nil
+	}
+
+	target := fmt.Sprintf("cluster '%s'", ctx.Cluster)
+	if ctx.Name != "" {
+		target = fmt.Sprintf("context '%s' (%s)", ctx.Name, target)
+	}
+	fmt.Fprintf(os.Stderr, "WARNING: %s is protected\n", target)
+	fmt.Fprintf(os.Stderr, "About to %s\n", action)
+
+	answer, err := prompt("Type the cluster name to continue: ")
+	if err != nil {
+		return fmt.Errorf("%s is protected, pass --yes to confirm", target)
+	}
+	if answer != ctx.Cluster {
+		return fmt.Errorf("confirmation did not match cluster '%s', aborting", ctx.Cluster)
+	}
+	return nil
+}
@@ -1,10 +1,17 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/yogendratamang48/ecs/pkg/aws"
+	"github.com/yogendratamang48/ecs/pkg/config"
+	"github.com/yogendratamang48/ecs/pkg/types"
 )
 
 // These variables will be set by GoReleaser
@@ -14,6 +21,15 @@ var (
 	date    = "unknown"
 )
 
+// Global flags that override the active context for a single invocation
+var overrides config.Overrides
+
+// requestTimeout limits every request to AWS, set by --request-timeout
+var requestTimeout time.Duration
+
+// verbosity traces the AWS API calls to stderr, set by -v/--verbosity
+var verbosity int
+
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Print version information of ecs cli",
@@ -39,14 +55,89 @@ var rootCmd = &cobra.Command{
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	err := rootCmd.Execute()
+	args, err := expandAliases(os.Args[1:])
 	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
 		os.Exit(1)
 	}
+	rootCmd.SetArgs(args)
+
+	// Ctrl-C and SIGTERM cancel the context of the running command, so that
+	// requests are aborted and streams and sessions are closed cleanly
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err = rootCmd.ExecuteContext(ctx)
+	stop()
+	if saveErr := saveRecording(); saveErr != nil {
+		fmt.Fprintln(os.Stderr, "Error:", saveErr)
+		err = saveErr
+	}
+	if err != nil {
+		os.Exit(1)
+	}
+}
+
+// contextOverrides returns the global override flags, falling back to
+// ECS_CONTEXT/ECS_CLUSTER for the ones that weren't passed
+func contextOverrides() config.Overrides {
+	o := overrides
+	if o.Context == "" {
+		o.Context = os.Getenv("ECS_CONTEXT")
+	}
+	if o.Cluster == "" {
+		o.Cluster = os.Getenv("ECS_CLUSTER")
+	}
+	return o
+}
+
+// currentContext resolves the context for this invocation, applying the
+// global overrides on top of the config file
+func currentContext() (*types.Context, error) {
+	return configManager.ResolveContext(contextOverrides())
+}
+
+// newSession builds the AWS session shared by every client of this invocation
+func newSession(ctx *types.Context) (*aws.Session, error) {
+	backend, err := selectedBackend()
+	if err != nil {
+		return nil, err
+	}
+	opts := aws.Options{
+		CacheDir:       configManager.CacheDir(),
+		RequestTimeout: requestTimeout,
+		Backend:        backend,
+		Verbosity:      verbosity,
+	}
+	if err := cassetteOptions(&opts); err != nil {
+		return nil, err
+	}
+	return aws.NewSession(ctx, opts)
+}
+
+// newECSClient creates an ECS client for the context from a new session
+func newECSClient(ctx *types.Context) (*aws.ECSClient, error) {
+	session, err := newSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return session.ECS(), nil
 }
 
 func init() {
 	cobra.OnInitialize(initConfig)
+
+	flags := rootCmd.PersistentFlags()
+	flags.StringVar(&configFiles, "config", "", "Config files to merge, separated by ':' (env: ECS_CONFIG, default: $HOME/.ecs/config.yaml or $XDG_CONFIG_HOME/ecs/config.yaml)")
+	flags.StringVar(&overrides.Context, "context", "", "Name of the context to use instead of current-context (env: ECS_CONTEXT)")
+	flags.StringVar(&overrides.Cluster, "cluster", "", "ECS cluster to use for this invocation (env: ECS_CLUSTER)")
+	flags.StringVar(&overrides.Region, "region", "", "AWS region to use for this invocation")
+	flags.StringVar(&overrides.Profile, "profile", "", "AWS profile to use for this invocation")
+	flags.DurationVar(&requestTimeout, "request-timeout", 0, "Timeout for each request to AWS, e.g. 30s (default: no timeout)")
+	flags.StringVar(&backendName, "backend", "", "Backend serving requests: aws, or fake for an in-memory demo cluster (default: aws)")
+	flags.StringVar(&fixtureFile, "fixture", "", "YAML file describing the clusters of the fake backend (implies --backend fake)")
+	flags.IntVarP(&verbosity, "verbosity", "v", 0, "Log AWS API calls to stderr: 1 for each call with its latency and request ID, 2 for each retry too")
+	flags.StringVar(&recordFile, "record", "", "Record the AWS requests and responses of the command to a cassette file, with credentials scrubbed")
+	flags.StringVar(&replayFile, "replay", "", "Answer AWS requests from a cassette file recorded with --record instead of calling AWS")
+
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(configCmd())
 	rootCmd.AddCommand(getCmd())
@@ -55,4 +146,5 @@ func init() {
 	rootCmd.AddCommand(scaleCmd())
 	rootCmd.AddCommand(logsCmd())
 	rootCmd.AddCommand(execCmd())
+	rootCmd.AddCommand(loginCmd())
 }
@@ -2,15 +2,16 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
 
 	"github.com/spf13/cobra"
-	"github.com/yogendratamang48/ecs/pkg/aws"
 )
 
 func scaleCmd() *cobra.Command {
-	var replicas int32
+	var (
+		replicas int32
+		yes      bool
+	)
 
 	cmd := &cobra.Command{
 		Use:   "scale SERVICE_NAME",
@@ -29,19 +30,24 @@ Example:
 			serviceName := args[0]
 
 			// Get current context
-			ctx, err := configManager.GetContext()
+			ctx, err := currentContext()
 			if err != nil {
 				return fmt.Errorf("failed to get current context: %w", err)
 			}
 
+			action := fmt.Sprintf("scale service %s to %d replicas", serviceName, replicas)
+			if err := confirmProtected(ctx, action, yes); err != nil {
+				return err
+			}
+
 			// Create ECS client
-			client, err := aws.NewECSClient(ctx)
+			client, err := newECSClient(ctx)
 			if err != nil {
 				return fmt.Errorf("failed to create ECS client: %w", err)
 			}
 
 			// Scale the service
-			if err := client.ScaleService(context.Background(), serviceName, replicas); err != nil {
+			if err := client.ScaleService(cmd.Context(), serviceName, replicas); err != nil {
 				return fmt.Errorf("failed to scale service: %w", err)
 			}
 
@@ -52,6 +58,7 @@ Example:
 
 	cmd.Flags().Int32Var(&replicas, "replicas", 1, "Number of desired tasks")
 	cmd.MarkFlagRequired("replicas")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip the confirmation required by protected contexts")
 
 	return cmd
 }
@@ -0,0 +1,346 @@
+// pkg/aws/cassette.go
+package aws
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+)
+
+// cassetteVersion is the version of the cassette file format
+const cassetteVersion = 1
+
+// scrubbed replaces secrets in recorded responses
+const scrubbed = "REDACTED"
+
+// Cassette is a recording of the ECS, CloudWatch Logs and SSM requests a
+// command made and of the responses it got, written by a Recorder and
+// played back by a Replayer
+type Cassette struct {
+	Version      int           `json:"version"`
+	RecordedAt   time.Time     `json:"recordedAt"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a request and its response
+type Interaction struct {
+	// Operation identifies the API call, e.g.
+	// AmazonEC2ContainerServiceV20141113.ListServices
+	Operation string           `json:"operation"`
+	Request   RecordedRequest  `json:"request"`
+	Response  RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a request without its credentials: only the headers
+// in recordedHeaders are kept
+type RecordedRequest struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is a response with the secrets in scrubbedFields
+// replaced
+type RecordedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// recordedHeaders are the request headers kept in cassettes. The others
+// carry the signature, credentials or details of the recording machine.
+var recordedHeaders = []string{"Content-Type", "X-Amz-Target"}
+
+// scrubbedFields are the JSON fields of responses holding secrets, such as
+// the token of an ExecuteCommand session
+var scrubbedFields = map[string]bool{
+	"accesskeyid":     true,
+	"secretaccesskey": true,
+	"sessiontoken":    true,
+	"tokenvalue":      true,
+}
+
+// LoadCassette reads a cassette file
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("invalid cassette %s: %w", path, err)
+	}
+	if c.Version != cassetteVersion {
+		return nil, fmt.Errorf("unsupported cassette version %d in %s", c.Version, path)
+	}
+	return &c, nil
+}
+
+// Save writes the cassette to path, readable by the owner only
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0600)
+}
+
+// Recorder is an HTTP client recording the requests it sends, and the
+// responses it gets, to a cassette. It is safe for concurrent use, so one
+// Recorder can be shared by every session of an invocation.
+type Recorder struct {
+	path string
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder returns a recorder writing to path. An empty cassette is
+// written right away, so that an unwritable path fails early.
+func NewRecorder(path string) (*Recorder, error) {
+	r := &Recorder{
+		path: path,
+		cassette: Cassette{
+			Version:      cassetteVersion,
+			RecordedAt:   time.Now().UTC(),
+			Interactions: []Interaction{},
+		},
+	}
+	if err := r.Save(); err != nil {
+		return nil, fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return r, nil
+}
+
+// Save writes the interactions recorded so far to the cassette file
+func (r *Recorder) Save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cassette.Save(r.path)
+}
+
+// Client returns an HTTP client sending requests with next, or with a
+// default client when next is nil, and recording them
+func (r *Recorder) Client(next aws.HTTPClient) aws.HTTPClient {
+	if next == nil {
+		next = awshttp.NewBuildableClient()
+	}
+	return &recordingClient{recorder: r, next: next}
+}
+
+type recordingClient struct {
+	recorder *Recorder
+	next     aws.HTTPClient
+}
+
+func (c *recordingClient) Do(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.next.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	interaction := Interaction{
+		Operation: operation(req),
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Body:   string(reqBody),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Headers:    resp.Header.Clone(),
+			Body:       string(scrubJSON(respBody)),
+		},
+	}
+	for _, name := range recordedHeaders {
+		if value := req.Header.Get(name); value != "" {
+			if interaction.Request.Headers == nil {
+				interaction.Request.Headers = make(http.Header)
+			}
+			interaction.Request.Headers.Set(name, value)
+		}
+	}
+	// The length changes when secrets are scrubbed, and the date would make
+	// the SDK correct its clock on replay
+	interaction.Response.Headers.Del("Content-Length")
+	interaction.Response.Headers.Del("Date")
+
+	c.recorder.mu.Lock()
+	c.recorder.cassette.Interactions = append(c.recorder.cassette.Interactions, interaction)
+	c.recorder.mu.Unlock()
+
+	return resp, nil
+}
+
+// Replayer is an HTTP client answering requests from a cassette instead of
+// sending them. It is safe for concurrent use.
+type Replayer struct {
+	path string
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+// NewReplayer returns a replayer answering from the cassette file at path
+func NewReplayer(path string) (*Replayer, error) {
+	c, err := LoadCassette(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Replayer{
+		path:     path,
+		cassette: c,
+		used:     make([]bool, len(c.Interactions)),
+	}, nil
+}
+
+// CassetteMismatchError is returned by a Replayer for a request that isn't
+// in the cassette, or that was made more often than recorded
+type CassetteMismatchError struct {
+	Operation string
+	Path      string
+}
+
+func (e *CassetteMismatchError) Error() string {
+	return fmt.Sprintf("no recorded response left for %s in cassette %s", e.Operation, e.Path)
+}
+
+// RetryableError tells the SDK not to retry the request, as the cassette
+// would not answer it either
+func (e *CassetteMismatchError) RetryableError() bool {
+	return false
+}
+
+// Do answers the request with the first unused interaction of the same
+// operation, preferring one with the same request body. Falling back to
+// the operation alone keeps replays working when bodies hold times, such as
+// the start time of GetLogEvents, or another cluster name.
+func (r *Replayer) Do(req *http.Request) (*http.Response, error) {
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	op := operation(req)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	match := -1
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] || interaction.Operation != op {
+			continue
+		}
+		if interaction.Request.Body == string(body) {
+			match = i
+			break
+		}
+		if match < 0 {
+			match = i
+		}
+	}
+	if match < 0 {
+		return nil, &CassetteMismatchError{Operation: op, Path: r.path}
+	}
+	r.used[match] = true
+
+	recorded := r.cassette.Interactions[match].Response
+	header := recorded.Headers.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}, nil
+}
+
+// operation identifies the API call of a request: the X-Amz-Target header
+// of JSON protocols, else the method and path
+func operation(req *http.Request) string {
+	if target := req.Header.Get("X-Amz-Target"); target != "" {
+		return target
+	}
+	return req.Method + " " + req.URL.Path
+}
+
+// readBody reads a request or response body and replaces it with a reader
+// of the same bytes
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+// scrubJSON replaces the values of scrubbedFields in a JSON document. Other
+// bodies are returned as is.
+func scrubJSON(data []byte) []byte {
+	var doc interface{}
+	if len(data) == 0 || json.Unmarshal(data, &doc) != nil {
+		return data
+	}
+	if !scrubValue(doc) {
+		return data
+	}
+	scrubbedData, err := json.Marshal(doc)
+	if err != nil {
+		return data
+	}
+	return scrubbedData
+}
+
+// scrubValue scrubs v in place and reports whether anything was replaced
+func scrubValue(v interface{}) bool {
+	changed := false
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if _, ok := value.(string); ok && scrubbedFields[strings.ToLower(key)] {
+				v[key] = scrubbed
+				changed = true
+				continue
+			}
+			changed = scrubValue(value) || changed
+		}
+	case []interface{}:
+		for _, value := range v {
+			changed = scrubValue(value) || changed
+		}
+	}
+	return changed
+}
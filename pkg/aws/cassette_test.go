@@ -0,0 +1,66 @@
+// pkg/aws/cassette_test.go
+package aws
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestScrubJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "execute command session",
+			in:   `{"session":{"sessionId":"s-1","streamUrl":"wss://x","tokenValue":"secret"},"taskArn":"t"}`,
+			want: `{"session":{"sessionId":"s-1","streamUrl":"wss://x","tokenValue":"REDACTED"},"taskArn":"t"}`,
+		},
+		{
+			name: "credentials in any case",
+			in:   `{"roleCredentials":{"AccessKeyId":"AKIA","secretAccessKey":"s","SESSIONTOKEN":"t","expiration":1}}`,
+			want: `{"roleCredentials":{"AccessKeyId":"REDACTED","secretAccessKey":"REDACTED","SESSIONTOKEN":"REDACTED","expiration":1}}`,
+		},
+		{
+			name: "nested in arrays",
+			in:   `{"items":[{"tokenValue":"a"},{"name":"b"},[{"sessionToken":"c"}]]}`,
+			want: `{"items":[{"tokenValue":"REDACTED"},{"name":"b"},[{"sessionToken":"REDACTED"}]]}`,
+		},
+		{
+			name: "non-string values are kept",
+			in:   `{"tokenValue":null,"sessionToken":{"value":"x"}}`,
+			want: `{"tokenValue":null,"sessionToken":{"value":"x"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got, want interface{}
+			if err := json.Unmarshal(scrubJSON([]byte(tt.in)), &got); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("scrubJSON(%s) = %v, want %v", tt.in, got, want)
+			}
+		})
+	}
+}
+
+func TestScrubJSONUnchanged(t *testing.T) {
+	// Bodies without secrets, and bodies that aren't JSON, are kept byte for
+	// byte so that replayed requests still match
+	for _, in := range []string{
+		"",
+		"not json",
+		`{"services": ["web", "api"],  "cluster":"demo"}`,
+		`[1, 2, 3]`,
+	} {
+		if got := string(scrubJSON([]byte(in))); got != in {
+			t.Errorf("scrubJSON(%q) = %q, want it unchanged", in, got)
+		}
+	}
+}
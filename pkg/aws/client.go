@@ -2,76 +2,47 @@
 package aws
 
 import (
-	"context"
-
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
-	"github.com/aws/aws-sdk-go-v2/service/ecs"
-	"github.com/aws/aws-sdk-go-v2/service/ssm"
 	"github.com/yogendratamang48/ecs/pkg/types"
 )
 
 // ECSClient wraps the ECS client and provides additional context
 type ECSClient struct {
-	*ecs.Client
+	Client  ECSAPI
 	Context *types.Context
+	session *Session
 }
 
 type CloudWatchClient struct {
-	*cloudwatchlogs.Client
+	Client  LogsAPI
 	Context *types.Context
 }
 
 type SSMClient struct {
-	*ssm.Client
+	Client  SSMAPI
 	Context *types.Context
 }
 
 func NewCloudWatchLogsClient(ctx *types.Context) (*CloudWatchClient, error) {
-	// Load AWS configuration
-	cfg, err := config.LoadDefaultConfig(context.Background(),
-		config.WithRegion(ctx.Region),
-		config.WithSharedConfigProfile(ctx.Profile),
-	)
+	session, err := NewSession(ctx, Options{})
 	if err != nil {
 		return nil, err
 	}
-
-	return &CloudWatchClient{
-		Client:  cloudwatchlogs.NewFromConfig(cfg),
-		Context: ctx,
-	}, nil
+	return session.CloudWatchLogs(), nil
 }
 
 // NewECSClient creates a new ECS client with the given context
 func NewECSClient(ctx *types.Context) (*ECSClient, error) {
-	// Load AWS configuration
-	cfg, err := config.LoadDefaultConfig(context.Background(),
-		config.WithRegion(ctx.Region),
-		config.WithSharedConfigProfile(ctx.Profile),
-	)
+	session, err := NewSession(ctx, Options{})
 	if err != nil {
 		return nil, err
 	}
-
-	return &ECSClient{
-		Client:  ecs.NewFromConfig(cfg),
-		Context: ctx,
-	}, nil
+	return session.ECS(), nil
 }
 
 func NewSSMClient(ctx *types.Context) (*SSMClient, error) {
-	// Load AWS configuration
-	cfg, err := config.LoadDefaultConfig(context.Background(),
-		config.WithRegion(ctx.Region),
-		config.WithSharedConfigProfile(ctx.Profile),
-	)
+	session, err := NewSession(ctx, Options{})
 	if err != nil {
 		return nil, err
 	}
-
-	return &SSMClient{
-		Client:  ssm.NewFromConfig(cfg),
-		Context: ctx,
-	}, nil
+	return session.SSM(), nil
 }
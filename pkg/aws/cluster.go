@@ -0,0 +1,46 @@
+// pkg/aws/cluster.go
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+)
+
+// GetClusterStatus returns the status of the context's cluster, e.g. ACTIVE
+func (c *ECSClient) GetClusterStatus(ctx context.Context) (string, error) {
+	result, err := c.Client.DescribeClusters(ctx, &ecs.DescribeClustersInput{
+		Clusters: []string{c.Context.Cluster},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if len(result.Clusters) == 0 {
+		return "", fmt.Errorf("cluster %s not found", c.Context.Cluster)
+	}
+
+	return aws.ToString(result.Clusters[0].Status), nil
+}
+
+// ListClusters returns the names of all clusters in the context's region
+func (c *ECSClient) ListClusters(ctx context.Context) ([]string, error) {
+	var clusters []string
+
+	paginator := ecs.NewListClustersPaginator(c.Client, &ecs.ListClustersInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, arn := range page.ClusterArns {
+			// Cluster ARNs end in cluster/NAME
+			clusters = append(clusters, arn[strings.LastIndex(arn, "/")+1:])
+		}
+	}
+
+	return clusters, nil
+}
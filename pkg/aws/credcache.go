@@ -0,0 +1,156 @@
+// pkg/aws/credcache.go
+package aws
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/yogendratamang48/ecs/pkg/types"
+)
+
+// cachedCredentialsMinTTL is how long cached credentials must remain valid
+// for them to be reused instead of retrieving new ones
+const cachedCredentialsMinTTL = 5 * time.Minute
+
+// fileCredentialsCache persists temporary credentials on disk so that
+// back-to-back invocations against the same context reuse them
+type fileCredentialsCache struct {
+	path     string
+	provider aws.CredentialsProvider
+}
+
+type cachedCredentials struct {
+	AccessKeyID     string    `json:"accessKeyId"`
+	SecretAccessKey string    `json:"secretAccessKey"`
+	SessionToken    string    `json:"sessionToken"`
+	Expires         time.Time `json:"expires"`
+}
+
+func newFileCredentialsCache(dir string, ctx *types.Context, provider aws.CredentialsProvider) *fileCredentialsCache {
+	return &fileCredentialsCache{
+		path:     filepath.Join(dir, credentialsCacheKey(ctx)+".json"),
+		provider: provider,
+	}
+}
+
+// credentialsCacheKey identifies the credentials of a context by everything
+// that influences them, so an overridden profile, region or endpoint never
+// reuses stale entries
+func credentialsCacheKey(ctx *types.Context) string {
+	h := sha256.Sum256([]byte(strings.Join([]string{
+		ctx.Name,
+		ctx.Profile,
+		ctx.Region,
+		ctx.EndpointURL,
+		ctx.Credentials.Source,
+		ctx.Credentials.Process,
+		ctx.Credentials.WebIdentityTokenFile,
+		ctx.RoleArn,
+		ctx.ExternalID,
+		ctx.SessionName,
+		ctx.SessionDuration.String(),
+		ctx.MFASerial,
+		ctx.SSO.StartURL,
+		ctx.SSO.Region,
+		ctx.SSO.AccountID,
+		ctx.SSO.RoleName,
+	}, "\x00")))
+	return hex.EncodeToString(h[:])
+}
+
+// Retrieve returns the cached credentials if they are still valid, and
+// otherwise retrieves and caches new ones from the wrapped provider
+func (c *fileCredentialsCache) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	if creds, ok := c.load(); ok {
+		return creds, nil
+	}
+
+	creds, err := c.provider.Retrieve(ctx)
+	if err != nil {
+		return aws.Credentials{}, err
+	}
+
+	// Failing to write the cache only costs a re-assume on the next run
+	if creds.CanExpire {
+		_ = c.store(creds)
+	}
+
+	return creds, nil
+}
+
+func (c *fileCredentialsCache) load() (aws.Credentials, bool) {
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return aws.Credentials{}, false
+	}
+
+	var cached cachedCredentials
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return aws.Credentials{}, false
+	}
+
+	if time.Until(cached.Expires) < cachedCredentialsMinTTL {
+		return aws.Credentials{}, false
+	}
+
+	return aws.Credentials{
+		AccessKeyID:     cached.AccessKeyID,
+		SecretAccessKey: cached.SecretAccessKey,
+		SessionToken:    cached.SessionToken,
+		Source:          "ecs-cli credential cache",
+		CanExpire:       true,
+		Expires:         cached.Expires,
+	}, true
+}
+
+func (c *fileCredentialsCache) store(creds aws.Credentials) error {
+	data, err := json.Marshal(cachedCredentials{
+		AccessKeyID:     creds.AccessKeyID,
+		SecretAccessKey: creds.SecretAccessKey,
+		SessionToken:    creds.SessionToken,
+		Expires:         creds.Expires,
+	})
+	if err != nil {
+		return err
+	}
+
+	// MkdirAll leaves the mode of an existing directory alone, and only the
+	// user may be able to replace cached credentials
+	dir := filepath.Dir(c.path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	if err := os.Chmod(dir, 0700); err != nil {
+		return err
+	}
+
+	return writePrivateFile(c.path, data)
+}
+
+// writePrivateFile writes data readable by the user only to a temporary file
+// next to path and renames it into place. A temporary file of its own keeps
+// concurrent invocations, and other tools sharing the file, from renaming
+// each other's half-written files into place.
+func writePrivateFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,81 @@
+// pkg/aws/credsource.go
+package aws
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/processcreds"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/yogendratamang48/ecs/pkg/types"
+)
+
+// usesProfile reports whether the base credentials of the context come from
+// its profile in the shared AWS config
+func usesProfile(ctx *types.Context) bool {
+	source := ctx.Credentials.Source
+	return (source == "" || source == types.CredentialsFromProfile) && ctx.SSO.StartURL == ""
+}
+
+// assumesRole reports whether the context's role is assumed on top of the
+// base credentials. With a web identity the role is assumed directly.
+func assumesRole(ctx *types.Context) bool {
+	return ctx.RoleArn != "" && ctx.Credentials.Source != types.CredentialsFromWebIdentity
+}
+
+// baseCredentials returns the provider of the base credentials of the
+// context, or nil when the credentials of the loaded profile are used
+func baseCredentials(cfg aws.Config, ctx *types.Context) (aws.CredentialsProvider, error) {
+	switch ctx.Credentials.Source {
+	case "", types.CredentialsFromProfile:
+		if ctx.SSO.StartURL != "" {
+			return ssoCredentialsProvider(cfg, ctx.SSO)
+		}
+		return nil, nil
+
+	case types.CredentialsFromEnv:
+		env, err := config.NewEnvConfig()
+		if err != nil {
+			return nil, err
+		}
+		if !env.Credentials.HasKeys() {
+			return nil, fmt.Errorf("no credentials in the environment, set AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+		}
+		return credentials.StaticCredentialsProvider{Value: env.Credentials}, nil
+
+	case types.CredentialsFromProcess:
+		if ctx.Credentials.Process == "" {
+			return nil, fmt.Errorf("no credential process command set")
+		}
+		return processcreds.NewProvider(ctx.Credentials.Process), nil
+
+	case types.CredentialsFromWebIdentity:
+		tokenFile := ctx.Credentials.WebIdentityTokenFile
+		if tokenFile == "" {
+			tokenFile = os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE")
+		}
+		roleArn := ctx.RoleArn
+		if roleArn == "" {
+			roleArn = os.Getenv("AWS_ROLE_ARN")
+		}
+		if tokenFile == "" || roleArn == "" {
+			return nil, fmt.Errorf("web identity credentials need a token file and a role ARN (or AWS_WEB_IDENTITY_TOKEN_FILE and AWS_ROLE_ARN)")
+		}
+
+		return stscreds.NewWebIdentityRoleProvider(sts.NewFromConfig(cfg), roleArn, stscreds.IdentityTokenFile(tokenFile), func(o *stscreds.WebIdentityRoleOptions) {
+			if ctx.SessionName != "" {
+				o.RoleSessionName = ctx.SessionName
+			}
+			if ctx.SessionDuration > 0 {
+				o.Duration = ctx.SessionDuration
+			}
+		}), nil
+
+	default:
+		return nil, fmt.Errorf("unknown credentials source %q", ctx.Credentials.Source)
+	}
+}
@@ -6,15 +6,65 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
 )
 
+// pluginShutdownTimeout is how long the session-manager-plugin gets to close
+// the session after a cancellation before it is killed
+const pluginShutdownTimeout = 5 * time.Second
+
+// MinSessionManagerPluginVersion is the oldest session-manager-plugin
+// release known to work with ECS execute-command
+const MinSessionManagerPluginVersion = "1.2.0.0"
+
+// SessionManagerPluginVersion returns the version of the installed
+// session-manager-plugin
+func SessionManagerPluginVersion() (string, error) {
+	pluginPath, err := exec.LookPath("session-manager-plugin")
+	if err != nil {
+		return "", fmt.Errorf("session-manager-plugin not found: %w", err)
+	}
+
+	out, err := exec.Command(pluginPath, "--version").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get session-manager-plugin version: %w", err)
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+// CompareVersions compares two dotted numeric versions, returning -1, 0 or 1
+func CompareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
 // ExecuteCommand runs a command on a container in a task
 func (c *ECSClient) ExecuteCommand(ctx context.Context, taskID string, interactive bool, container string, command string) error {
 	// Note: AWS ECS execute-command API only supports interactive mode
 	// So we'll ignore the interactive parameter and always use interactive mode
-	
+
 	// Create the ECS execute-command API call
 	execCommandInput := &ecs.ExecuteCommandInput{
 		Cluster:     &c.Context.Cluster,
@@ -76,8 +126,24 @@ func (c *ECSClient) ExecuteCommand(ctx context.Context, taskID string, interacti
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
+	// On cancellation ask the plugin to close the session before killing it
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(syscall.SIGTERM)
+	}
+	cmd.WaitDelay = pluginShutdownTimeout
+
 	// Run the plugin and wait for it to complete
-	if err := cmd.Run(); err != nil {
+	err = cmd.Run()
+	if ctx.Err() != nil {
+		// Don't leave the session open on the container until it times out
+		terminateCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), pluginShutdownTimeout)
+		defer cancel()
+		c.session.SSM().Client.TerminateSession(terminateCtx, &ssm.TerminateSessionInput{
+			SessionId: &sessionId,
+		})
+		return ctx.Err()
+	}
+	if err != nil {
 		return fmt.Errorf("session-manager-plugin failed: %w", err)
 	}
 
@@ -0,0 +1,121 @@
+// pkg/aws/identity.go
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamTypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// RequiredActions are the IAM actions used by the ECS CLI commands
+var RequiredActions = []string{
+	"ecs:ListServices",
+	"ecs:DescribeServices",
+	"ecs:UpdateService",
+	"ecs:ListTasks",
+	"ecs:DescribeTasks",
+	"ecs:DescribeTaskDefinition",
+	"ecs:StopTask",
+	"ecs:ExecuteCommand",
+	"logs:GetLogEvents",
+}
+
+// ErrOfflineBackend is returned for calls that need AWS credentials on a
+// session served by an offline backend or replayed from a cassette
+var ErrOfflineBackend = errors.New("not available with an offline backend")
+
+var regionPattern = regexp.MustCompile(`^[a-z]{2}(-[a-z]+)+-\d+$`)
+
+// ValidRegion reports whether region looks like an AWS region name
+func ValidRegion(region string) bool {
+	return regionPattern.MatchString(region)
+}
+
+// Credentials resolves the session's credentials and returns where they came from
+func (s *Session) Credentials(ctx context.Context) (string, error) {
+	if s.offline() {
+		return "", ErrOfflineBackend
+	}
+	creds, err := s.config.Credentials.Retrieve(ctx)
+	if err != nil {
+		return "", err
+	}
+	return creds.Source, nil
+}
+
+// CallerIdentity returns the ARN of the principal behind the session's credentials
+func (s *Session) CallerIdentity(ctx context.Context) (string, error) {
+	if s.offline() {
+		return "", ErrOfflineBackend
+	}
+	result, err := sts.NewFromConfig(s.config).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(result.Arn), nil
+}
+
+// SimulateActions evaluates the principal's IAM policies for the given
+// actions and returns the ones that are not allowed
+func (s *Session) SimulateActions(ctx context.Context, callerArn string, actions []string) ([]string, error) {
+	if s.offline() {
+		return nil, ErrOfflineBackend
+	}
+	client := iam.NewFromConfig(s.config)
+
+	principalArn, err := policySourceArn(ctx, client, callerArn)
+	if err != nil {
+		return nil, err
+	}
+
+	var denied []string
+	paginator := iam.NewSimulatePrincipalPolicyPaginator(client, &iam.SimulatePrincipalPolicyInput{
+		PolicySourceArn: aws.String(principalArn),
+		ActionNames:     actions,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range page.EvaluationResults {
+			if result.EvalDecision != iamTypes.PolicyEvaluationDecisionTypeAllowed {
+				denied = append(denied, aws.ToString(result.EvalActionName))
+			}
+		}
+	}
+
+	return denied, nil
+}
+
+// policySourceArn maps a caller identity to the IAM entity whose policies
+// apply to it. Assumed-role sessions are mapped to their role, whose path
+// is not part of the session ARN and has to be looked up.
+func policySourceArn(ctx context.Context, client *iam.Client, callerArn string) (string, error) {
+	parts := strings.Split(callerArn, ":")
+	if len(parts) != 6 {
+		return "", fmt.Errorf("unexpected caller ARN %s", callerArn)
+	}
+
+	resource := parts[5]
+	switch {
+	case strings.HasPrefix(resource, "assumed-role/"):
+		roleName := strings.Split(resource, "/")[1]
+		role, err := client.GetRole(ctx, &iam.GetRoleInput{RoleName: aws.String(roleName)})
+		if err != nil {
+			return "", err
+		}
+		return aws.ToString(role.Role.Arn), nil
+	case strings.HasPrefix(resource, "user/"), strings.HasPrefix(resource, "role/"):
+		return callerArn, nil
+	default:
+		return "", fmt.Errorf("policies of %s cannot be simulated", callerArn)
+	}
+}
@@ -17,7 +17,8 @@ type LogEvent struct {
 	Message   string
 }
 
-// GetTaskLogs retrieves logs for a specific task
+// GetTaskLogs retrieves logs for a specific task. The channel is closed when
+// all logs have been read, or when ctx is done while following.
 func (c *ECSClient) GetTaskLogs(ctx context.Context, taskID string, follow bool, since time.Duration, container string) (<-chan LogEvent, error) {
 	// Get task details to find the log configuration
 	input := &ecs.DescribeTasksInput{
@@ -65,11 +66,8 @@ func (c *ECSClient) GetTaskLogs(ctx context.Context, taskID string, follow bool,
 	logGroup := options["awslogs-group"]
 	logStream := fmt.Sprintf("%s/%s/%s", options["awslogs-stream-prefix"], *targetContainer.Name, taskID)
 
-	// Create CloudWatch Logs client
-	cwlClient, err := NewCloudWatchLogsClient(c.Context)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create Cloudwatch client: %w", err)
-	}
+	// Create CloudWatch Logs client sharing this client's credentials
+	cwlClient := c.session.CloudWatchLogs()
 
 	// Calculate start time
 	startTime := time.Now().Add(-since).UnixMilli()
@@ -90,16 +88,23 @@ func (c *ECSClient) GetTaskLogs(ctx context.Context, taskID string, follow bool,
 				NextToken:     nextToken,
 			}
 
-			logEvents, err := cwlClient.GetLogEvents(ctx, getLogsInput)
+			logEvents, err := cwlClient.Client.GetLogEvents(ctx, getLogsInput)
 			if err != nil {
-				fmt.Printf("Error fetching logs: %v\n", err)
+				// A cancelled context ends the stream quietly
+				if ctx.Err() == nil {
+					fmt.Printf("Error fetching logs: %v\n", err)
+				}
 				return
 			}
 
 			for _, event := range logEvents.Events {
-				logChan <- LogEvent{
+				select {
+				case logChan <- LogEvent{
 					Timestamp: *event.Timestamp,
 					Message:   *event.Message,
+				}:
+				case <-ctx.Done():
+					return
 				}
 			}
 
@@ -109,7 +114,11 @@ func (c *ECSClient) GetTaskLogs(ctx context.Context, taskID string, follow bool,
 
 			// If following, wait before next poll
 			if len(logEvents.Events) == 0 {
-				time.Sleep(time.Second)
+				select {
+				case <-time.After(time.Second):
+				case <-ctx.Done():
+					return
+				}
 			}
 
 			nextToken = logEvents.NextForwardToken
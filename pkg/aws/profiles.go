@@ -0,0 +1,91 @@
+// pkg/aws/profiles.go
+package aws
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+)
+
+// SharedConfigProfiles returns the names of the profiles defined in the
+// shared AWS config and credentials files
+func SharedConfigProfiles() ([]string, error) {
+	configFile := os.Getenv("AWS_CONFIG_FILE")
+	if configFile == "" {
+		configFile = config.DefaultSharedConfigFilename()
+	}
+	credentialsFile := os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+	if credentialsFile == "" {
+		credentialsFile = config.DefaultSharedCredentialsFilename()
+	}
+
+	seen := make(map[string]bool)
+	for _, file := range []struct {
+		path   string
+		prefix string
+	}{
+		// Profiles other than default are prefixed in the config file only
+		{configFile, "profile "},
+		{credentialsFile, ""},
+	} {
+		sections, err := iniSections(file.path)
+		if err != nil {
+			return nil, err
+		}
+		for _, section := range sections {
+			switch {
+			case section == "default":
+				seen[section] = true
+			case file.prefix == "":
+				seen[section] = true
+			case strings.HasPrefix(section, file.prefix):
+				seen[strings.TrimSpace(strings.TrimPrefix(section, file.prefix))] = true
+			}
+		}
+	}
+
+	var profiles []string
+	for profile := range seen {
+		profiles = append(profiles, profile)
+	}
+	sort.Strings(profiles)
+
+	return profiles, nil
+}
+
+// ProfileRegion returns the region configured for a shared config profile
+func ProfileRegion(ctx context.Context, profile string) string {
+	cfg, err := config.LoadSharedConfigProfile(ctx, profile)
+	if err != nil {
+		return ""
+	}
+	return cfg.Region
+}
+
+// iniSections returns the section names of an ini file, which may not exist
+func iniSections(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var sections []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			sections = append(sections, strings.TrimSpace(line[1:len(line)-1]))
+		}
+	}
+
+	return sections, scanner.Err()
+}
@@ -0,0 +1,112 @@
+// pkg/aws/retry.go
+package aws
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/yogendratamang48/ecs/pkg/types"
+)
+
+// retryer returns the retryer set up by the context's retry settings, or
+// nil to keep the SDK's default. The settings of a context take precedence
+// over AWS_RETRY_MODE and AWS_MAX_ATTEMPTS.
+func retryer(ctx *types.Context) func() aws.Retryer {
+	settings := ctx.Retry
+	if settings.MaxAttempts == 0 && settings.Mode == "" {
+		return nil
+	}
+
+	standard := func(o *retry.StandardOptions) {
+		if settings.MaxAttempts > 0 {
+			o.MaxAttempts = settings.MaxAttempts
+		}
+	}
+	return func() aws.Retryer {
+		if settings.Mode == types.RetryModeAdaptive {
+			return retry.NewAdaptiveMode(func(o *retry.AdaptiveModeOptions) {
+				o.StandardOptions = append(o.StandardOptions, standard)
+			})
+		}
+		return retry.NewStandard(standard)
+	}
+}
+
+// tokenBucket limits requests to rate per second, allowing bursts of up to
+// burst requests. Waiting requests are served in order.
+type tokenBucket struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket returns the limiter of the context's retry settings, or nil
+// when requests are not limited
+func newTokenBucket(settings types.Retry) *tokenBucket {
+	if settings.RateLimit <= 0 {
+		return nil
+	}
+	burst := float64(settings.Burst)
+	if burst <= 0 {
+		burst = math.Max(1, math.Floor(settings.RateLimit))
+	}
+	return &tokenBucket{
+		rate:   settings.RateLimit,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+// wait blocks until a request may be sent, or ctx is done
+func (b *tokenBucket) wait(ctx context.Context) error {
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	// Taking the token ahead of time queues the request behind those that
+	// are already waiting
+	b.tokens--
+	delay := time.Duration(-b.tokens / b.rate * float64(time.Second))
+	b.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// addMiddleware makes every attempt of an API call, retries included, wait
+// for the bucket before it is signed and sent
+func (b *tokenBucket) addMiddleware(stack *middleware.Stack) error {
+	limit := middleware.FinalizeMiddlewareFunc("RateLimit", func(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+		if err := b.wait(ctx); err != nil {
+			return middleware.FinalizeOutput{}, middleware.Metadata{}, err
+		}
+		return next.HandleFinalize(ctx, in)
+	})
+
+	if _, ok := stack.Finalize.Get("Retry"); !ok {
+		return stack.Finalize.Add(limit, middleware.Before)
+	}
+	return stack.Finalize.Insert(limit, "Retry", middleware.After)
+}
@@ -0,0 +1,92 @@
+// pkg/aws/retry_test.go
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yogendratamang48/ecs/pkg/types"
+)
+
+func TestNewTokenBucket(t *testing.T) {
+	tests := []struct {
+		settings types.Retry
+		burst    float64
+	}{
+		{types.Retry{RateLimit: 20}, 20},
+		{types.Retry{RateLimit: 2.5}, 2},
+		{types.Retry{RateLimit: 0.5}, 1},
+		{types.Retry{RateLimit: 20, Burst: 50}, 50},
+		{types.Retry{RateLimit: 20, Burst: 1}, 1},
+	}
+	for _, tt := range tests {
+		b := newTokenBucket(tt.settings)
+		if b == nil {
+			t.Errorf("newTokenBucket(%+v) = nil", tt.settings)
+			continue
+		}
+		if b.burst != tt.burst || b.tokens != tt.burst || b.rate != tt.settings.RateLimit {
+			t.Errorf("newTokenBucket(%+v): rate %v burst %v tokens %v, want burst %v", tt.settings, b.rate, b.burst, b.tokens, tt.burst)
+		}
+	}
+
+	for _, settings := range []types.Retry{{}, {Burst: 5}, {RateLimit: -1}} {
+		if b := newTokenBucket(settings); b != nil {
+			t.Errorf("newTokenBucket(%+v) = %+v, want no limit", settings, b)
+		}
+	}
+}
+
+func TestTokenBucketWait(t *testing.T) {
+	b := newTokenBucket(types.Retry{RateLimit: 50, Burst: 5})
+	ctx := context.Background()
+
+	// The burst goes out at once
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if err := b.wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("burst took %v", elapsed)
+	}
+
+	// The next ten requests are spaced at the rate, 20ms apart
+	start = time.Now()
+	for i := 0; i < 10; i++ {
+		if err := b.wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond || elapsed > time.Second {
+		t.Errorf("10 requests at 50/s took %v, want about 200ms", elapsed)
+	}
+}
+
+func TestTokenBucketWaitCancelled(t *testing.T) {
+	b := newTokenBucket(types.Retry{RateLimit: 1, Burst: 1})
+	if err := b.wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := b.wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("wait() = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("cancelled wait took %v", elapsed)
+	}
+
+	// The cancelled request gave its token back instead of delaying the
+	// requests queued after it
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens < -0.1 {
+		t.Errorf("tokens = %v after a cancelled wait, want about 0", b.tokens)
+	}
+}
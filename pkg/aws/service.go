@@ -3,56 +3,111 @@ package aws
 
 import (
 	"context"
+	"sort"
+	"sync"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ecs"
 	"github.com/yogendratamang48/ecs/pkg/types"
 )
 
+const (
+	// listServicesPageSize is the largest page ListServices returns
+	listServicesPageSize = 100
+
+	// describeServicesBatchSize is the most services DescribeServices
+	// accepts at once
+	describeServicesBatchSize = 10
+
+	// describeServicesWorkers bounds the DescribeServices calls in flight
+	describeServicesWorkers = 5
+)
+
+// ListServices returns all services in the cluster, in the order ListServices
+// returns them. The services are described in batches, several at a time.
 func (c *ECSClient) ListServices(ctx context.Context) ([]*types.Service, error) {
-	var services []*types.Service
-	var nextToken *string
+	arns, err := c.listServiceArns(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	for {
-		// List service ARNs
-		input := &ecs.ListServicesInput{
-			Cluster:    &c.Context.Cluster,
-			NextToken:  nextToken,
-			MaxResults: aws.Int32(10),
-		}
+	var batches [][]string
+	for len(arns) > 0 {
+		n := min(describeServicesBatchSize, len(arns))
+		batches = append(batches, arns[:n])
+		arns = arns[n:]
+	}
 
-		result, err := c.Client.ListServices(ctx, input)
-		if err != nil {
-			return nil, err
-		}
+	// The first failure cancels the batches still in flight
+	workCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		results  = make([][]*types.Service, len(batches))
+		jobs     = make(chan int)
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	for w := 0; w < min(describeServicesWorkers, len(batches)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				services, err := c.describeServiceBatch(workCtx, batches[i])
+				if err != nil {
+					errOnce.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					continue
+				}
+				results[i] = services
+			}
+		}()
+	}
 
-		if len(result.ServiceArns) == 0 {
-			break
+feed:
+	for i := range batches {
+		select {
+		case jobs <- i:
+		case <-workCtx.Done():
+			break feed
 		}
+	}
+	close(jobs)
+	wg.Wait()
 
-		// Describe services to get detailed information
-		describeInput := &ecs.DescribeServicesInput{
-			Cluster:  &c.Context.Cluster,
-			Services: result.ServiceArns,
-		}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var services []*types.Service
+	for _, batch := range results {
+		services = append(services, batch...)
+	}
+	return services, nil
+}
 
-		describeResult, err := c.Client.DescribeServices(ctx, describeInput)
+// listServiceArns returns the ARNs of all services in the cluster, paging
+// ListServices at its largest page size
+func (c *ECSClient) listServiceArns(ctx context.Context) ([]string, error) {
+	var arns []string
+	var nextToken *string
+
+	for {
+		result, err := c.Client.ListServices(ctx, &ecs.ListServicesInput{
+			Cluster:    &c.Context.Cluster,
+			NextToken:  nextToken,
+			MaxResults: aws.Int32(listServicesPageSize),
+		})
 		if err != nil {
 			return nil, err
 		}
-
-		// Convert to our service type
-		for _, svc := range describeResult.Services {
-			services = append(services, &types.Service{
-				Name:         *svc.ServiceName,
-				Status:       string(*svc.Status),
-				TaskDef:      *svc.TaskDefinition,
-				DesiredCount: int(svc.DesiredCount),
-				RunningCount: int(svc.RunningCount),
-				PendingCount: int(svc.PendingCount),
-				CreatedAt:    *svc.CreatedAt,
-			})
-		}
+		arns = append(arns, result.ServiceArns...)
 
 		if result.NextToken == nil {
 			break
@@ -60,6 +115,43 @@ func (c *ECSClient) ListServices(ctx context.Context) ([]*types.Service, error)
 		nextToken = result.NextToken
 	}
 
+	return arns, nil
+}
+
+// describeServiceBatch describes up to describeServicesBatchSize services,
+// returning them in the order of arns. Services that no longer exist are
+// left out.
+func (c *ECSClient) describeServiceBatch(ctx context.Context, arns []string) ([]*types.Service, error) {
+	result, err := c.Client.DescribeServices(ctx, &ecs.DescribeServicesInput{
+		Cluster:  &c.Context.Cluster,
+		Services: arns,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// DescribeServices doesn't promise to keep the order of its input
+	position := make(map[string]int, len(arns))
+	for i, arn := range arns {
+		position[arn] = i
+	}
+	described := result.Services
+	sort.SliceStable(described, func(i, j int) bool {
+		return position[aws.ToString(described[i].ServiceArn)] < position[aws.ToString(described[j].ServiceArn)]
+	})
+
+	services := make([]*types.Service, 0, len(described))
+	for _, svc := range described {
+		services = append(services, &types.Service{
+			Name:         *svc.ServiceName,
+			Status:       string(*svc.Status),
+			TaskDef:      *svc.TaskDefinition,
+			DesiredCount: int(svc.DesiredCount),
+			RunningCount: int(svc.RunningCount),
+			PendingCount: int(svc.PendingCount),
+			CreatedAt:    *svc.CreatedAt,
+		})
+	}
 	return services, nil
 }
 
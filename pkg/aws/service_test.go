@@ -0,0 +1,188 @@
+// pkg/aws/service_test.go
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	ecstypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/yogendratamang48/ecs/pkg/types"
+)
+
+// stubServices serves ListServices and DescribeServices for a cluster of
+// numbered services. The other ECS calls are not implemented.
+type stubServices struct {
+	ECSAPI
+
+	names []string
+
+	// describe, when set, runs at the start of every DescribeServices call
+	describe func(ctx context.Context, arns []string) error
+
+	mu            sync.Mutex
+	listCalls     int
+	describeCalls int
+}
+
+func newStubServices(n int) *stubServices {
+	s := &stubServices{}
+	for i := 0; i < n; i++ {
+		s.names = append(s.names, fmt.Sprintf("svc-%03d", i))
+	}
+	return s
+}
+
+func serviceArn(name string) string {
+	return "arn:aws:ecs:us-east-1:000000000000:service/demo/" + name
+}
+
+func (s *stubServices) ListServices(ctx context.Context, params *ecs.ListServicesInput, optFns ...func(*ecs.Options)) (*ecs.ListServicesOutput, error) {
+	s.mu.Lock()
+	s.listCalls++
+	s.mu.Unlock()
+
+	if n := aws.ToInt32(params.MaxResults); n != listServicesPageSize {
+		return nil, fmt.Errorf("MaxResults = %d", n)
+	}
+	start, _ := strconv.Atoi(aws.ToString(params.NextToken))
+	end := min(start+listServicesPageSize, len(s.names))
+
+	out := &ecs.ListServicesOutput{}
+	for _, name := range s.names[start:end] {
+		out.ServiceArns = append(out.ServiceArns, serviceArn(name))
+	}
+	if end < len(s.names) {
+		out.NextToken = aws.String(strconv.Itoa(end))
+	}
+	return out, nil
+}
+
+func (s *stubServices) DescribeServices(ctx context.Context, params *ecs.DescribeServicesInput, optFns ...func(*ecs.Options)) (*ecs.DescribeServicesOutput, error) {
+	s.mu.Lock()
+	s.describeCalls++
+	s.mu.Unlock()
+
+	if len(params.Services) > describeServicesBatchSize {
+		return nil, fmt.Errorf("%d services described at once", len(params.Services))
+	}
+	if s.describe != nil {
+		if err := s.describe(ctx, params.Services); err != nil {
+			return nil, err
+		}
+	}
+
+	// Answer in reverse order, since DescribeServices doesn't keep it
+	out := &ecs.DescribeServicesOutput{}
+	created := time.Unix(0, 0)
+	for i := len(params.Services) - 1; i >= 0; i-- {
+		arn := params.Services[i]
+		name := arn[len(serviceArn("")):]
+		out.Services = append(out.Services, ecstypes.Service{
+			ServiceArn:     aws.String(arn),
+			ServiceName:    aws.String(name),
+			Status:         aws.String("ACTIVE"),
+			TaskDefinition: aws.String("arn:aws:ecs:us-east-1:000000000000:task-definition/" + name + ":1"),
+			DesiredCount:   1,
+			RunningCount:   1,
+			CreatedAt:      &created,
+		})
+	}
+	return out, nil
+}
+
+func newStubClient(stub *stubServices) *ECSClient {
+	return &ECSClient{Client: stub, Context: &types.Context{Cluster: "demo"}}
+}
+
+func TestListServicesOrder(t *testing.T) {
+	tests := []struct {
+		services  int
+		listCalls int
+	}{
+		{0, 1},
+		{1, 1},
+		{10, 1},
+		{11, 1},
+		{100, 1},
+		{253, 3},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.services), func(t *testing.T) {
+			stub := newStubServices(tt.services)
+			// Random latencies make batches finish out of order
+			stub.describe = func(context.Context, []string) error {
+				time.Sleep(time.Duration(rand.Intn(5)) * time.Millisecond)
+				return nil
+			}
+
+			services, err := newStubClient(stub).ListServices(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(services) != tt.services {
+				t.Fatalf("got %d services, want %d", len(services), tt.services)
+			}
+			for i, svc := range services {
+				if svc.Name != stub.names[i] {
+					t.Fatalf("service %d is %s, want %s", i, svc.Name, stub.names[i])
+				}
+			}
+
+			batches := (tt.services + describeServicesBatchSize - 1) / describeServicesBatchSize
+			if stub.listCalls != tt.listCalls || stub.describeCalls != batches {
+				t.Errorf("%d ListServices and %d DescribeServices calls, want %d and %d", stub.listCalls, stub.describeCalls, tt.listCalls, batches)
+			}
+		})
+	}
+}
+
+func TestListServicesErrorCancelsBatches(t *testing.T) {
+	stub := newStubServices(300)
+	failure := errors.New("access denied")
+	stub.describe = func(ctx context.Context, arns []string) error {
+		if arns[0] == serviceArn("svc-000") {
+			return failure
+		}
+		// The other batches only finish once they are cancelled
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(10 * time.Second):
+			return nil
+		}
+	}
+
+	start := time.Now()
+	services, err := newStubClient(stub).ListServices(context.Background())
+	if !errors.Is(err, failure) {
+		t.Fatalf("ListServices() = %d services, %v, want %v", len(services), err, failure)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("ListServices took %v, the batches in flight were not cancelled", elapsed)
+	}
+	if stub.describeCalls >= 30 {
+		t.Errorf("%d DescribeServices calls, want the remaining batches to be skipped", stub.describeCalls)
+	}
+}
+
+func TestListServicesCancelled(t *testing.T) {
+	stub := newStubServices(50)
+	ctx, cancel := context.WithCancel(context.Background())
+	stub.describe = func(ctx context.Context, arns []string) error {
+		cancel()
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	if _, err := newStubClient(stub).ListServices(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("ListServices() error = %v, want %v", err, context.Canceled)
+	}
+}
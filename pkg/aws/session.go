@@ -0,0 +1,240 @@
+// pkg/aws/session.go
+package aws
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/yogendratamang48/ecs/pkg/types"
+)
+
+// Options configures how a Session is built
+type Options struct {
+	// CacheDir is where temporary credentials are cached between
+	// invocations. Caching is disabled when empty.
+	CacheDir string
+
+	// RequestTimeout limits each HTTP request to AWS, and every retry gets
+	// the full timeout again. There is no limit when zero.
+	RequestTimeout time.Duration
+
+	// Backend serves the API calls instead of AWS when set. No AWS
+	// configuration or credentials are loaded then.
+	Backend Backend
+
+	// Recorder, if set, records the ECS, CloudWatch Logs and SSM requests
+	// and responses. Requests for credentials are not recorded.
+	Recorder *Recorder
+
+	// Replayer, if set, answers requests from a cassette instead of AWS.
+	// No AWS configuration or credentials are loaded then.
+	Replayer *Replayer
+
+	// Verbosity traces the ECS, CloudWatch Logs and SSM calls to stderr:
+	// TraceOperations logs a line per call, TraceAttempts a line per retry
+	// as well
+	Verbosity int
+}
+
+// Session holds the AWS configuration resolved for a context once per
+// invocation, so that every service client built from it shares the same
+// credentials instead of resolving them again
+type Session struct {
+	Context  *types.Context
+	config   aws.Config
+	backend  Backend
+	tracer   *tracer
+	recorder *Recorder
+	replay   bool
+}
+
+// NewSession loads the AWS configuration for a context, assuming the
+// context's role on top of the base credentials when one is set. The base
+// credentials come from the profile unless the context selects SSO, the
+// environment, a credential process or a web identity token.
+// The context's endpoint URL, if any, and its retry and rate limiting
+// settings apply to every service client.
+func NewSession(ctx *types.Context, opts Options) (*Session, error) {
+	if opts.Backend != nil {
+		return &Session{
+			Context: ctx,
+			backend: opts.Backend,
+		}, nil
+	}
+
+	if opts.Replayer != nil {
+		// Requests are answered from the cassette whatever the endpoint,
+		// but the SDK still needs a valid region to build them
+		region := ctx.Region
+		if region == "" {
+			region = "us-east-1"
+		}
+		cfg := aws.Config{
+			Region:      region,
+			Credentials: aws.AnonymousCredentials{},
+			HTTPClient:  opts.Replayer,
+		}
+		return &Session{
+			Context: ctx,
+			config:  cfg,
+			tracer:  newTracer(opts.Verbosity, os.Stderr),
+			replay:  true,
+		}, nil
+	}
+
+	loadOptions := []func(*config.LoadOptions) error{
+		config.WithRegion(ctx.Region),
+	}
+	// Only profile credentials read the context's profile, so that other
+	// sources work without a shared AWS config
+	if usesProfile(ctx) && ctx.Profile != "" {
+		loadOptions = append(loadOptions, config.WithSharedConfigProfile(ctx.Profile))
+	}
+	if opts.RequestTimeout > 0 {
+		loadOptions = append(loadOptions, config.WithHTTPClient(
+			awshttp.NewBuildableClient().WithTimeout(opts.RequestTimeout),
+		))
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.Background(), loadOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	if ctx.EndpointURL != "" {
+		cfg.BaseEndpoint = aws.String(ctx.EndpointURL)
+	}
+	if r := retryer(ctx); r != nil {
+		cfg.Retryer = r
+	}
+	// Added before any client is built, so that the calls made for
+	// credentials are rate limited as well. They are not traced, as their
+	// parameters hold tokens and secrets.
+	if limiter := newTokenBucket(ctx.Retry); limiter != nil {
+		cfg.APIOptions = append(cfg.APIOptions, limiter.addMiddleware)
+	}
+
+	base, err := baseCredentials(cfg, ctx)
+	if err != nil {
+		return nil, err
+	}
+	if base != nil {
+		cfg.Credentials = aws.NewCredentialsCache(base)
+	}
+
+	if assumesRole(ctx) {
+		var provider aws.CredentialsProvider = assumeRoleProvider(cfg, ctx)
+		if opts.CacheDir != "" {
+			provider = newFileCredentialsCache(opts.CacheDir, ctx, provider)
+		}
+		cfg.Credentials = aws.NewCredentialsCache(provider)
+	}
+
+	return &Session{
+		Context:  ctx,
+		config:   cfg,
+		tracer:   newTracer(opts.Verbosity, os.Stderr),
+		recorder: opts.Recorder,
+	}, nil
+}
+
+// offline reports whether the session's requests are served without AWS
+func (s *Session) offline() bool {
+	return s.backend != nil || s.replay
+}
+
+// assumeRoleProvider returns a provider that assumes the context's role
+// using the base credentials in cfg
+func assumeRoleProvider(cfg aws.Config, ctx *types.Context) aws.CredentialsProvider {
+	return stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), ctx.RoleArn, func(o *stscreds.AssumeRoleOptions) {
+		if ctx.ExternalID != "" {
+			o.ExternalID = aws.String(ctx.ExternalID)
+		}
+		if ctx.SessionName != "" {
+			o.RoleSessionName = ctx.SessionName
+		}
+		if ctx.SessionDuration > 0 {
+			o.Duration = ctx.SessionDuration
+		}
+		if ctx.MFASerial != "" {
+			o.SerialNumber = aws.String(ctx.MFASerial)
+			o.TokenProvider = stscreds.StdinTokenProvider
+		}
+	})
+}
+
+// ECS returns an ECS client backed by the session
+func (s *Session) ECS() *ECSClient {
+	var client ECSAPI = s.backend
+	if s.backend == nil {
+		client = ecs.NewFromConfig(s.config, func(o *ecs.Options) {
+			if s.Context.Endpoints.ECS != "" {
+				o.BaseEndpoint = aws.String(s.Context.Endpoints.ECS)
+			}
+			if s.recorder != nil {
+				o.HTTPClient = s.recorder.Client(o.HTTPClient)
+			}
+			if s.tracer != nil {
+				o.APIOptions = append(o.APIOptions, s.tracer.addMiddleware)
+			}
+		})
+	}
+	return &ECSClient{
+		Client:  client,
+		Context: s.Context,
+		session: s,
+	}
+}
+
+// CloudWatchLogs returns a CloudWatch Logs client backed by the session
+func (s *Session) CloudWatchLogs() *CloudWatchClient {
+	var client LogsAPI = s.backend
+	if s.backend == nil {
+		client = cloudwatchlogs.NewFromConfig(s.config, func(o *cloudwatchlogs.Options) {
+			if s.Context.Endpoints.Logs != "" {
+				o.BaseEndpoint = aws.String(s.Context.Endpoints.Logs)
+			}
+			if s.recorder != nil {
+				o.HTTPClient = s.recorder.Client(o.HTTPClient)
+			}
+			if s.tracer != nil {
+				o.APIOptions = append(o.APIOptions, s.tracer.addMiddleware)
+			}
+		})
+	}
+	return &CloudWatchClient{
+		Client:  client,
+		Context: s.Context,
+	}
+}
+
+// SSM returns an SSM client backed by the session
+func (s *Session) SSM() *SSMClient {
+	var client SSMAPI = s.backend
+	if s.backend == nil {
+		client = ssm.NewFromConfig(s.config, func(o *ssm.Options) {
+			if s.Context.Endpoints.SSM != "" {
+				o.BaseEndpoint = aws.String(s.Context.Endpoints.SSM)
+			}
+			if s.recorder != nil {
+				o.HTTPClient = s.recorder.Client(o.HTTPClient)
+			}
+			if s.tracer != nil {
+				o.APIOptions = append(o.APIOptions, s.tracer.addMiddleware)
+			}
+		})
+	}
+	return &SSMClient{
+		Client:  client,
+		Context: s.Context,
+	}
+}
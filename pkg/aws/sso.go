@@ -0,0 +1,176 @@
+// pkg/aws/sso.go
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/ssocreds"
+	"github.com/aws/aws-sdk-go-v2/service/sso"
+	"github.com/aws/aws-sdk-go-v2/service/ssooidc"
+	oidctypes "github.com/aws/aws-sdk-go-v2/service/ssooidc/types"
+	"github.com/yogendratamang48/ecs/pkg/types"
+)
+
+const deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+// ssoTokenFile returns where the access token of the SSO settings is cached.
+// The location is shared with the AWS CLI, so either tool can sign in.
+func ssoTokenFile(s types.SSO) (string, error) {
+	key := s.StartURL
+	if s.Session != "" {
+		key = s.Session
+	}
+	return ssocreds.StandardCachedTokenFilepath(key)
+}
+
+// ssoCredentialsProvider returns a provider for the credentials of the SSO
+// role, refreshing the cached access token when it has expired
+func ssoCredentialsProvider(cfg aws.Config, s types.SSO) (aws.CredentialsProvider, error) {
+	tokenFile, err := ssoTokenFile(s)
+	if err != nil {
+		return nil, err
+	}
+
+	oidc := ssooidc.NewFromConfig(cfg, func(o *ssooidc.Options) {
+		o.Region = s.Region
+	})
+	client := sso.NewFromConfig(cfg, func(o *sso.Options) {
+		o.Region = s.Region
+	})
+
+	return &ssoLoginHint{
+		provider: ssocreds.New(client, s.AccountID, s.RoleName, s.StartURL, func(o *ssocreds.Options) {
+			o.SSOTokenProvider = ssocreds.NewSSOTokenProvider(oidc, tokenFile)
+		}),
+		startURL: s.StartURL,
+	}, nil
+}
+
+// ssoLoginHint points at 'ecs login' when SSO credentials can't be retrieved,
+// which mostly means the session has expired or was never started
+type ssoLoginHint struct {
+	provider aws.CredentialsProvider
+	startURL string
+}
+
+func (p *ssoLoginHint) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	creds, err := p.provider.Retrieve(ctx)
+	if err != nil {
+		return aws.Credentials{}, fmt.Errorf("no valid SSO session for %s, run 'ecs login': %w", p.startURL, err)
+	}
+	return creds, nil
+}
+
+// ssoToken is an access token in the cache format of the AWS CLI and SDKs
+type ssoToken struct {
+	StartURL              string `json:"startUrl"`
+	Region                string `json:"region"`
+	AccessToken           string `json:"accessToken"`
+	ExpiresAt             string `json:"expiresAt"`
+	RefreshToken          string `json:"refreshToken,omitempty"`
+	ClientID              string `json:"clientId"`
+	ClientSecret          string `json:"clientSecret"`
+	RegistrationExpiresAt string `json:"registrationExpiresAt"`
+}
+
+// SSOLogin signs in to IAM Identity Center with the device authorization
+// flow and caches the access token. confirm is called with the URL the user
+// has to open and the code shown there. It returns when the token expires.
+func SSOLogin(ctx context.Context, s types.SSO, confirm func(url, code string)) (time.Time, error) {
+	client := ssooidc.New(ssooidc.Options{Region: s.Region})
+
+	registration, err := client.RegisterClient(ctx, &ssooidc.RegisterClientInput{
+		ClientName: aws.String("ecs-cli"),
+		ClientType: aws.String("public"),
+		Scopes:     []string{"sso:account:access"},
+		GrantTypes: []string{deviceCodeGrantType, "refresh_token"},
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to register client: %w", err)
+	}
+
+	authorization, err := client.StartDeviceAuthorization(ctx, &ssooidc.StartDeviceAuthorizationInput{
+		ClientId:     registration.ClientId,
+		ClientSecret: registration.ClientSecret,
+		StartUrl:     aws.String(s.StartURL),
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to start device authorization: %w", err)
+	}
+
+	url := aws.ToString(authorization.VerificationUriComplete)
+	if url == "" {
+		url = aws.ToString(authorization.VerificationUri)
+	}
+	confirm(url, aws.ToString(authorization.UserCode))
+
+	interval := time.Duration(max(authorization.Interval, 1)) * time.Second
+	for {
+		select {
+		case <-ctx.Done():
+			return time.Time{}, ctx.Err()
+		case <-time.After(interval):
+		}
+
+		token, err := client.CreateToken(ctx, &ssooidc.CreateTokenInput{
+			ClientId:     registration.ClientId,
+			ClientSecret: registration.ClientSecret,
+			DeviceCode:   authorization.DeviceCode,
+			GrantType:    aws.String(deviceCodeGrantType),
+		})
+
+		var pending *oidctypes.AuthorizationPendingException
+		var slowDown *oidctypes.SlowDownException
+		switch {
+		case errors.As(err, &pending):
+			continue
+		case errors.As(err, &slowDown):
+			interval += 5 * time.Second
+			continue
+		case err != nil:
+			return time.Time{}, fmt.Errorf("failed to create token: %w", err)
+		}
+
+		expires := time.Now().Add(time.Duration(token.ExpiresIn) * time.Second).UTC()
+		cached := ssoToken{
+			StartURL:              s.StartURL,
+			Region:                s.Region,
+			AccessToken:           aws.ToString(token.AccessToken),
+			ExpiresAt:             expires.Format(time.RFC3339),
+			RefreshToken:          aws.ToString(token.RefreshToken),
+			ClientID:              aws.ToString(registration.ClientId),
+			ClientSecret:          aws.ToString(registration.ClientSecret),
+			RegistrationExpiresAt: time.Unix(registration.ClientSecretExpiresAt, 0).UTC().Format(time.RFC3339),
+		}
+		if err := storeSSOToken(s, cached); err != nil {
+			return time.Time{}, fmt.Errorf("failed to cache token: %w", err)
+		}
+		return expires, nil
+	}
+}
+
+// storeSSOToken writes the token to the shared SSO token cache
+func storeSSOToken(s types.SSO, token ssoToken) error {
+	path, err := ssoTokenFile(s)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	return writePrivateFile(path, data)
+}
@@ -0,0 +1,309 @@
+// pkg/aws/trace.go
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// Verbosity levels of API tracing
+const (
+	// TraceOperations logs a line per API call with its parameters,
+	// latency, attempts and request ID
+	TraceOperations = 1
+
+	// TraceAttempts also logs a line per HTTP attempt, showing retries and
+	// throttling as they happen
+	TraceAttempts = 2
+)
+
+// redacted replaces the values of sensitive parameters
+const redacted = "<redacted>"
+
+// maxTracedValues is how many items of a list parameter are shown before
+// only their count is
+const maxTracedValues = 3
+
+// traceMu keeps the lines of concurrent calls, possibly of different
+// sessions, from interleaving
+var traceMu sync.Mutex
+
+// tracer logs the API calls of the clients it is added to
+type tracer struct {
+	verbosity int
+	out       io.Writer
+}
+
+// callTrace is the state of a single API call, shared by its attempts
+type callTrace struct {
+	attempts  int
+	throttled int
+}
+
+type callTraceKey struct{}
+
+// newTracer returns a tracer writing to out, or nil when verbosity is zero
+func newTracer(verbosity int, out io.Writer) *tracer {
+	if verbosity <= 0 {
+		return nil
+	}
+	return &tracer{verbosity: verbosity, out: out}
+}
+
+// addMiddleware adds the tracing middleware to the stack of an API call
+func (t *tracer) addMiddleware(stack *middleware.Stack) error {
+	err := stack.Initialize.Add(middleware.InitializeMiddlewareFunc("TraceOperation", t.traceOperation), middleware.After)
+	if err != nil {
+		return err
+	}
+
+	// Attempts are traced inside the retry loop, when there is one, and
+	// after the rate limiter so that the time spent waiting for it isn't
+	// counted
+	anchor := "Retry"
+	if _, ok := stack.Finalize.Get("RateLimit"); ok {
+		anchor = "RateLimit"
+	} else if _, ok := stack.Finalize.Get("Retry"); !ok {
+		return nil
+	}
+	return stack.Finalize.Insert(middleware.FinalizeMiddlewareFunc("TraceAttempt", t.traceAttempt), anchor, middleware.After)
+}
+
+func (t *tracer) traceOperation(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+	call := &callTrace{}
+	ctx = context.WithValue(ctx, callTraceKey{}, call)
+
+	start := time.Now()
+	out, metadata, err := next.HandleInitialize(ctx, in)
+	elapsed := time.Since(start)
+
+	var line strings.Builder
+	fmt.Fprintf(&line, "%s %s", awsmiddleware.GetServiceID(ctx), awsmiddleware.GetOperationName(ctx))
+	if params := summarizeParams(in.Parameters); params != "" {
+		fmt.Fprintf(&line, " %s", params)
+	}
+	fmt.Fprintf(&line, " -> %s in %s", outcome(err), formatLatency(elapsed))
+	if id := requestID(metadata, err); id != "" {
+		fmt.Fprintf(&line, " request-id=%s", id)
+	}
+	if call.attempts > 1 {
+		fmt.Fprintf(&line, " retries=%d", call.attempts-1)
+	}
+	if call.throttled > 0 {
+		fmt.Fprintf(&line, " throttled=%d", call.throttled)
+	}
+	t.logf("%s", line.String())
+
+	return out, metadata, err
+}
+
+func (t *tracer) traceAttempt(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+	call, _ := ctx.Value(callTraceKey{}).(*callTrace)
+	if call == nil {
+		return next.HandleFinalize(ctx, in)
+	}
+	call.attempts++
+	attempt := call.attempts
+
+	start := time.Now()
+	out, metadata, err := next.HandleFinalize(ctx, in)
+	elapsed := time.Since(start)
+
+	if isThrottle(err) {
+		call.throttled++
+	}
+
+	if t.verbosity >= TraceAttempts {
+		var line strings.Builder
+		fmt.Fprintf(&line, "  attempt %d: %s in %s", attempt, attemptStatus(metadata, err), formatLatency(elapsed))
+		if id := requestID(metadata, err); id != "" {
+			fmt.Fprintf(&line, " request-id=%s", id)
+		}
+		if isThrottle(err) {
+			line.WriteString(" (throttled)")
+		}
+		t.logf("%s", line.String())
+	}
+
+	return out, metadata, err
+}
+
+func (t *tracer) logf(format string, args ...interface{}) {
+	traceMu.Lock()
+	defer traceMu.Unlock()
+	fmt.Fprintf(t.out, "%s "+format+"\n", append([]interface{}{time.Now().Format("15:04:05.000")}, args...)...)
+}
+
+// outcome returns OK, or the error code of a failed call
+func outcome(err error) string {
+	if err == nil {
+		return "OK"
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode()
+	}
+	if errors.Is(err, context.Canceled) {
+		return "canceled"
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+	return "error"
+}
+
+// attemptStatus returns the HTTP status of an attempt and its error code,
+// if any
+func attemptStatus(metadata middleware.Metadata, err error) string {
+	if err == nil {
+		if resp, ok := awsmiddleware.GetRawResponse(metadata).(*smithyhttp.Response); ok {
+			return fmt.Sprintf("HTTP %d", resp.StatusCode)
+		}
+		return "OK"
+	}
+
+	var respErr *awshttp.ResponseError
+	if errors.As(err, &respErr) {
+		return fmt.Sprintf("HTTP %d %s", respErr.HTTPStatusCode(), outcome(err))
+	}
+	return err.Error()
+}
+
+// requestID returns the AWS request ID of a response, successful or not
+func requestID(metadata middleware.Metadata, err error) string {
+	if id, ok := awsmiddleware.GetRequestIDMetadata(metadata); ok {
+		return id
+	}
+	var respErr *awshttp.ResponseError
+	if errors.As(err, &respErr) {
+		return respErr.ServiceRequestID()
+	}
+	return ""
+}
+
+// isThrottle reports whether an attempt failed because it was throttled
+func isThrottle(err error) bool {
+	if err == nil {
+		return false
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		if _, ok := retry.DefaultThrottleErrorCodes[apiErr.ErrorCode()]; ok {
+			return true
+		}
+	}
+	var respErr *awshttp.ResponseError
+	return errors.As(err, &respErr) && respErr.HTTPStatusCode() == http.StatusTooManyRequests
+}
+
+func formatLatency(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(10 * time.Millisecond).String()
+}
+
+// summarizeParams returns the set fields of an API input as name=value
+// pairs, e.g. cluster="prod" maxResults=100 tasks=[25 items]
+func summarizeParams(params interface{}) string {
+	v := reflect.ValueOf(params)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+
+	var pairs []string
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		value, ok := summarizeValue(v.Field(i))
+		if !ok {
+			continue
+		}
+		name := strings.ToLower(field.Name[:1]) + field.Name[1:]
+		if sensitiveParam(field.Name) {
+			value = redacted
+		}
+		pairs = append(pairs, name+"="+value)
+	}
+	return strings.Join(pairs, " ")
+}
+
+// sensitiveParam reports whether a parameter may hold a secret, such as a
+// token, a password or an MFA code. Pagination tokens are not secret.
+func sensitiveParam(name string) bool {
+	name = strings.ToLower(name)
+	if name == "nexttoken" {
+		return false
+	}
+	for _, word := range []string{"token", "secret", "password", "code", "externalid"} {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// summarizeValue formats a parameter, reporting false for unset ones
+func summarizeValue(v reflect.Value) (string, bool) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", false
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		if v.Len() == 0 {
+			return "", false
+		}
+		return fmt.Sprintf("%q", v.String()), true
+	case reflect.Bool, reflect.Int, reflect.Int32, reflect.Int64:
+		return fmt.Sprint(v.Interface()), true
+	case reflect.Slice:
+		if v.Len() == 0 {
+			return "", false
+		}
+		if v.Len() > maxTracedValues || v.Type().Elem().Kind() != reflect.String {
+			return fmt.Sprintf("[%d items]", v.Len()), true
+		}
+		values := make([]string, v.Len())
+		for i := range values {
+			values[i] = fmt.Sprintf("%q", v.Index(i).String())
+		}
+		return "[" + strings.Join(values, " ") + "]", true
+	case reflect.Struct:
+		if t, ok := v.Interface().(time.Time); ok {
+			return t.Format(time.RFC3339), true
+		}
+		return "{...}", true
+	case reflect.Map:
+		if v.Len() == 0 {
+			return "", false
+		}
+		return fmt.Sprintf("{%d entries}", v.Len()), true
+	}
+	return "", false
+}
@@ -0,0 +1,86 @@
+// pkg/config/aliases.go
+package config
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/yogendratamang48/ecs/pkg/types"
+)
+
+// Aliases returns the command aliases of all config files. An alias defined
+// in several files is taken from the first of them.
+func (m *Manager) Aliases() map[string]string {
+	aliases := make(map[string]string)
+	for _, f := range m.files {
+		for name, expansion := range f.config.Aliases {
+			if _, ok := aliases[name]; !ok {
+				aliases[name] = expansion
+			}
+		}
+	}
+	return aliases
+}
+
+// SetAlias creates or replaces a command alias
+func (m *Manager) SetAlias(name, expansion string) error {
+	if err := validateAliasName(name); err != nil {
+		return err
+	}
+	if strings.TrimSpace(expansion) == "" {
+		return fmt.Errorf("alias '%s' must expand to a command", name)
+	}
+
+	f, err := m.writeTarget()
+	if err != nil {
+		return err
+	}
+
+	return f.update(func(cfg *types.Config) error {
+		if cfg.Aliases == nil {
+			cfg.Aliases = make(map[string]string)
+		}
+		cfg.Aliases[name] = expansion
+		return nil
+	})
+}
+
+// DeleteAlias removes a command alias
+func (m *Manager) DeleteAlias(name string) error {
+	var target *configFile
+	for _, f := range m.files {
+		if _, ok := f.config.Aliases[name]; ok {
+			target = f
+			break
+		}
+	}
+	if target == nil {
+		return fmt.Errorf("alias '%s' not found", name)
+	}
+	if !target.writable {
+		return fmt.Errorf("cannot delete alias '%s': %w", name, &ReadOnlyError{Path: target.path})
+	}
+
+	return target.update(func(cfg *types.Config) error {
+		if _, ok := cfg.Aliases[name]; !ok {
+			return fmt.Errorf("alias '%s' not found", name)
+		}
+		delete(cfg.Aliases, name)
+		return nil
+	})
+}
+
+// validateAliasName checks that an alias can be typed as a command name
+func validateAliasName(name string) error {
+	if name == "" {
+		return fmt.Errorf("alias name cannot be empty")
+	}
+	if strings.HasPrefix(name, "-") {
+		return fmt.Errorf("alias name '%s' cannot start with '-'", name)
+	}
+	if strings.ContainsFunc(name, unicode.IsSpace) || strings.Contains(name, ".") {
+		return fmt.Errorf("alias name '%s' cannot contain spaces or '.'", name)
+	}
+	return nil
+}
@@ -0,0 +1,225 @@
+// pkg/config/bundle.go
+package config
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/yogendratamang48/ecs/pkg/types"
+	"gopkg.in/yaml.v2"
+)
+
+// BundleKind identifies an exported context bundle
+const BundleKind = "ContextBundle"
+
+// ConflictStrategy decides what happens when an imported context has the
+// same name as an existing one
+type ConflictStrategy string
+
+const (
+	ConflictSkip      ConflictStrategy = "skip"
+	ConflictOverwrite ConflictStrategy = "overwrite"
+	ConflictRename    ConflictStrategy = "rename"
+)
+
+// ParseConflictStrategy validates a conflict strategy name
+func ParseConflictStrategy(s string) (ConflictStrategy, error) {
+	switch strategy := ConflictStrategy(s); strategy {
+	case ConflictSkip, ConflictOverwrite, ConflictRename:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("unknown conflict strategy %q (skip|overwrite|rename)", s)
+	}
+}
+
+// ImportResult describes what happened to one imported context
+type ImportResult struct {
+	Name    string
+	SavedAs string
+	Action  string
+}
+
+// ExportContexts returns the named contexts, or all contexts when no names
+// are given, as a bundle
+func (m *Manager) ExportContexts(names ...string) (*types.ContextBundle, error) {
+	bundle := &types.ContextBundle{
+		APIVersion: APIVersion,
+		Kind:       BundleKind,
+	}
+
+	if len(names) == 0 {
+		contexts, _, err := m.ListContexts()
+		if err != nil {
+			return nil, err
+		}
+		for i := range contexts {
+			bundle.Contexts = append(bundle.Contexts, &contexts[i])
+		}
+		return bundle, nil
+	}
+
+	for _, name := range names {
+		ctx, err := m.GetContextByName(name)
+		if err != nil {
+			return nil, err
+		}
+		bundle.Contexts = append(bundle.Contexts, ctx)
+	}
+	return bundle, nil
+}
+
+// StripPersonal removes the settings of a bundle that only work for the person
+// exporting it: the MFA device, the credential process and the web identity
+// token file. Contexts that got their credentials from a process fall back to
+// their profile.
+func StripPersonal(bundle *types.ContextBundle) {
+	for _, ctx := range bundle.Contexts {
+		ctx.MFASerial = ""
+		ctx.Credentials.WebIdentityTokenFile = ""
+		if ctx.Credentials.Process != "" {
+			ctx.Credentials.Process = ""
+			if ctx.Credentials.Source == types.CredentialsFromProcess {
+				ctx.Credentials.Source = ""
+			}
+		}
+	}
+}
+
+// SensitiveSettings describes the settings of a context that run a command or
+// read a file on this machine to get credentials, or that send requests, and
+// the credentials signing them, to another endpoint than AWS. It is empty
+// when there are none.
+func SensitiveSettings(ctx *types.Context) []string {
+	var settings []string
+	if ctx.Credentials.Process != "" {
+		settings = append(settings, fmt.Sprintf("runs credential process: %s", ctx.Credentials.Process))
+	}
+	if ctx.Credentials.WebIdentityTokenFile != "" {
+		settings = append(settings, fmt.Sprintf("reads web identity token file: %s", ctx.Credentials.WebIdentityTokenFile))
+	}
+	if ctx.EndpointURL != "" {
+		settings = append(settings, fmt.Sprintf("sends all requests to endpoint: %s", ctx.EndpointURL))
+	}
+	for _, endpoint := range []struct{ service, url string }{
+		{"ECS", ctx.Endpoints.ECS},
+		{"CloudWatch Logs", ctx.Endpoints.Logs},
+		{"SSM", ctx.Endpoints.SSM},
+	} {
+		if endpoint.url != "" {
+			settings = append(settings, fmt.Sprintf("sends %s requests to endpoint: %s", endpoint.service, endpoint.url))
+		}
+	}
+	return settings
+}
+
+// MarshalBundle renders a bundle as YAML
+func MarshalBundle(bundle *types.ContextBundle) ([]byte, error) {
+	return yaml.Marshal(bundle)
+}
+
+// UnmarshalBundle parses a context bundle. The output of 'ecs config view'
+// and whole config files are accepted as well.
+func UnmarshalBundle(data []byte) (*types.ContextBundle, error) {
+	doc := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("could not parse bundle: %w", err)
+	}
+
+	switch kind, _ := doc["kind"].(string); kind {
+	case BundleKind:
+		if version, _ := doc["apiVersion"].(string); version != APIVersion {
+			return nil, fmt.Errorf("unsupported bundle apiVersion %q, this release supports %q", version, APIVersion)
+		}
+		var bundle types.ContextBundle
+		if err := yaml.Unmarshal(data, &bundle); err != nil {
+			return nil, fmt.Errorf("could not parse bundle: %w", err)
+		}
+		return &bundle, nil
+
+	case Kind, "":
+		if _, _, err := migrate(doc); err != nil {
+			return nil, err
+		}
+		upgraded, err := yaml.Marshal(doc)
+		if err != nil {
+			return nil, err
+		}
+		cfg := newConfig()
+		if err := yaml.Unmarshal(upgraded, cfg); err != nil {
+			return nil, fmt.Errorf("could not parse config: %w", err)
+		}
+
+		bundle := &types.ContextBundle{APIVersion: APIVersion, Kind: BundleKind}
+		for _, ctx := range cfg.Contexts {
+			bundle.Contexts = append(bundle.Contexts, ctx)
+		}
+		sort.Slice(bundle.Contexts, func(i, j int) bool {
+			return bundle.Contexts[i].Name < bundle.Contexts[j].Name
+		})
+		return bundle, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported kind %q, expected %s", kind, BundleKind)
+	}
+}
+
+// ImportContexts merges the contexts of a bundle into the writable config
+// file, resolving name conflicts with the given strategy
+func (m *Manager) ImportContexts(bundle *types.ContextBundle, strategy ConflictStrategy) ([]ImportResult, error) {
+	for _, ctx := range bundle.Contexts {
+		if err := m.ValidateContext(ctx); err != nil {
+			return nil, fmt.Errorf("invalid context in bundle: %w", err)
+		}
+	}
+
+	f, err := m.writeTarget()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []ImportResult
+	err = f.update(func(cfg *types.Config) error {
+		results = nil
+		exists := func(name string) bool {
+			if _, ok := cfg.Contexts[name]; ok {
+				return true
+			}
+			_, _, ok := m.lookup(name)
+			return ok
+		}
+
+		for _, ctx := range bundle.Contexts {
+			result := ImportResult{Name: ctx.Name, SavedAs: ctx.Name, Action: "imported"}
+
+			if exists(ctx.Name) {
+				switch strategy {
+				case ConflictSkip:
+					result.Action = "skipped"
+					results = append(results, result)
+					continue
+				case ConflictOverwrite:
+					if err := m.shadowed(ctx.Name, f); err != nil {
+						return err
+					}
+					result.Action = "overwritten"
+				case ConflictRename:
+					for i := 1; exists(result.SavedAs); i++ {
+						result.SavedAs = fmt.Sprintf("%s-%d", ctx.Name, i)
+					}
+					result.Action = "renamed"
+				}
+			}
+
+			c := *ctx
+			c.Name = result.SavedAs
+			cfg.Contexts[c.Name] = &c
+			results = append(results, result)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
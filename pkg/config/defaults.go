@@ -0,0 +1,66 @@
+// pkg/config/defaults.go
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/yogendratamang48/ecs/pkg/types"
+)
+
+// Defaults returns the command defaults for a context. Values set on the
+// context win over the global defaults, which are taken from the first config
+// file setting them.
+func (m *Manager) Defaults(ctx *types.Context) types.Defaults {
+	var d types.Defaults
+	if ctx != nil {
+		d = ctx.Defaults
+	}
+	for _, f := range m.files {
+		mergeDefaults(&d, f.config.Defaults)
+	}
+	return d
+}
+
+// SetDefault changes a global default addressed by its config key below
+// defaults, e.g. "output" or "logs.since"
+func (m *Manager) SetDefault(key, value string) error {
+	f, err := m.writeTarget()
+	if err != nil {
+		return err
+	}
+
+	return f.update(func(cfg *types.Config) error {
+		if err := setField(reflect.ValueOf(&cfg.Defaults).Elem(), strings.Split(key, "."), value); err != nil {
+			return fmt.Errorf("defaults.%s: %w", key, err)
+		}
+		return validateDefaults(cfg.Defaults)
+	})
+}
+
+// validateDefaults checks values that commands can't validate on their own
+func validateDefaults(d types.Defaults) error {
+	switch d.Output {
+	case "", "json", "yaml", "wide":
+		return nil
+	default:
+		return fmt.Errorf("unknown default output %q (json|yaml|wide)", d.Output)
+	}
+}
+
+// mergeDefaults fills the unset values of d from fallback
+func mergeDefaults(d *types.Defaults, fallback types.Defaults) {
+	if d.Output == "" {
+		d.Output = fallback.Output
+	}
+	if d.Logs.Since == 0 {
+		d.Logs.Since = fallback.Logs.Since
+	}
+	if d.Logs.Container == "" {
+		d.Logs.Container = fallback.Logs.Container
+	}
+	if d.Exec.Container == "" {
+		d.Exec.Container = fallback.Exec.Container
+	}
+}
@@ -0,0 +1,38 @@
+// pkg/config/errors.go
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNoConfigDir is returned when neither a home directory nor
+	// XDG_CONFIG_HOME is available to locate the default config file
+	ErrNoConfigDir = errors.New("could not determine the config directory, set HOME or XDG_CONFIG_HOME")
+
+	// ErrNoConfigFile is returned by writes when no config file is in use
+	ErrNoConfigFile = errors.New("no config file in use, set ECS_CONFIG or --config to save changes")
+
+	// ErrNoCurrentContext is returned when no context is selected and no
+	// cluster is given to build one from flags or the environment
+	ErrNoCurrentContext = errors.New("no current context set, use 'ecs config use-context' or pass --context or --cluster")
+)
+
+// ContextNotFoundError is returned when a named context doesn't exist
+type ContextNotFoundError struct {
+	Name string
+}
+
+func (e *ContextNotFoundError) Error() string {
+	return fmt.Sprintf("context '%s' not found", e.Name)
+}
+
+// ReadOnlyError is returned when a change targets a read-only config file
+type ReadOnlyError struct {
+	Path string
+}
+
+func (e *ReadOnlyError) Error() string {
+	return fmt.Sprintf("config file %s is read-only", e.Path)
+}
@@ -0,0 +1,85 @@
+// pkg/config/fields.go
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// setField sets the field of the struct v addressed by a dotted config key,
+// matching each part of the key against the fields' yaml names
+func setField(v reflect.Value, key []string, value string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]
+		if name == "" || name != key[0] {
+			continue
+		}
+
+		field := v.Field(i)
+		if len(key) == 1 {
+			return setValue(field, value)
+		}
+		if field.Kind() == reflect.Ptr && field.Type().Elem().Kind() == reflect.Struct {
+			if field.IsNil() {
+				field.Set(reflect.New(field.Type().Elem()))
+			}
+			field = field.Elem()
+		}
+		if field.Kind() != reflect.Struct {
+			return fmt.Errorf("%s has no field %q", key[0], key[1])
+		}
+		return setField(field, key[1:], value)
+	}
+	return fmt.Errorf("unknown key %q", key[0])
+}
+
+// setValue parses value into a field of a basic kind. An empty value clears
+// the field.
+func setValue(field reflect.Value, value string) error {
+	switch {
+	case value == "" && isBasic(field.Kind()):
+		field.Set(reflect.Zero(field.Type()))
+	case field.Type() == durationType:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+	case field.Kind() == reflect.String:
+		field.SetString(value)
+	case field.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case field.Kind() >= reflect.Int && field.Kind() <= reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case field.Kind() == reflect.Float32 || field.Kind() == reflect.Float64:
+		f, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	default:
+		return fmt.Errorf("a %s cannot be set from a string", field.Type())
+	}
+	return nil
+}
+
+// isBasic reports whether setValue can parse a field of kind k
+func isBasic(k reflect.Kind) bool {
+	return k == reflect.String || k == reflect.Bool ||
+		(k >= reflect.Int && k <= reflect.Int64) ||
+		k == reflect.Float32 || k == reflect.Float64
+}
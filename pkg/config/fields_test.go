@@ -0,0 +1,86 @@
+// pkg/config/fields_test.go
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yogendratamang48/ecs/pkg/types"
+)
+
+func TestSetField(t *testing.T) {
+	tests := []struct {
+		key   string
+		value string
+		want  func(ctx *types.Context)
+	}{
+		{"region", "eu-west-1", func(ctx *types.Context) { ctx.Region = "eu-west-1" }},
+		{"endpoints.ecs", "http://localhost:4566", func(ctx *types.Context) { ctx.Endpoints.ECS = "http://localhost:4566" }},
+		{"credentials.source", "env", func(ctx *types.Context) { ctx.Credentials.Source = "env" }},
+		{"protected", "true", func(ctx *types.Context) { ctx.Protected = true }},
+		{"session-duration", "2h", func(ctx *types.Context) { ctx.SessionDuration = 2 * time.Hour }},
+		{"retry.max-attempts", "7", func(ctx *types.Context) { ctx.Retry.MaxAttempts = 7 }},
+		{"retry.rate-limit", "2.5", func(ctx *types.Context) { ctx.Retry.RateLimit = 2.5 }},
+		{"defaults.logs.since", "15m", func(ctx *types.Context) { ctx.Defaults.Logs.Since = 15 * time.Minute }},
+
+		// An empty value clears the field
+		{"profile", "", func(ctx *types.Context) { ctx.Profile = "" }},
+		{"protected", "", func(ctx *types.Context) { ctx.Protected = false }},
+		{"retry.burst", "", func(ctx *types.Context) { ctx.Retry.Burst = 0 }},
+		{"retry.rate-limit", "", func(ctx *types.Context) { ctx.Retry.RateLimit = 0 }},
+		{"defaults.logs.since", "", func(ctx *types.Context) { ctx.Defaults.Logs.Since = 0 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			got := testContext()
+			if err := setField(reflect.ValueOf(got).Elem(), strings.Split(tt.key, "."), tt.value); err != nil {
+				t.Fatal(err)
+			}
+			want := testContext()
+			tt.want(want)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestSetFieldErrors(t *testing.T) {
+	tests := []struct {
+		key   string
+		value string
+		want  string
+	}{
+		{"nope", "x", `unknown key "nope"`},
+		{"endpoints.nope", "x", `unknown key "nope"`},
+		{"region.name", "x", `region has no field "name"`},
+		{"protected", "maybe", "invalid syntax"},
+		{"retry.max-attempts", "many", "invalid syntax"},
+		{"session-duration", "soon", "invalid duration"},
+		{"credentials", "env", "cannot be set from a string"},
+		{"credentials", "", "cannot be set from a string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			err := setField(reflect.ValueOf(testContext()).Elem(), strings.Split(tt.key, "."), tt.value)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("setField(%s, %q) error = %v, want %q", tt.key, tt.value, err, tt.want)
+			}
+		})
+	}
+}
+
+func testContext() *types.Context {
+	return &types.Context{
+		Name:            "dev",
+		Cluster:         "c1",
+		Profile:         "default",
+		Region:          "us-east-1",
+		SessionDuration: time.Hour,
+		Protected:       true,
+		Retry:           types.Retry{MaxAttempts: 3, RateLimit: 10, Burst: 5},
+		Defaults:        types.Defaults{Logs: types.LogsDefaults{Since: time.Hour}},
+	}
+}
@@ -0,0 +1,200 @@
+// pkg/config/file.go
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/yogendratamang48/ecs/pkg/types"
+	"gopkg.in/yaml.v2"
+)
+
+// configFile is a single file on the config search path
+type configFile struct {
+	path     string
+	config   *types.Config
+	writable bool
+}
+
+// pendingMigration records the content of a config file that was upgraded
+// in memory and still has to be written back
+type pendingMigration struct {
+	from string
+	data []byte
+}
+
+// newConfig returns an empty config document of the current version
+func newConfig() *types.Config {
+	return &types.Config{
+		APIVersion: APIVersion,
+		Kind:       Kind,
+		Contexts:   make(map[string]*types.Context),
+	}
+}
+
+// loadConfigFile reads a config file, upgrading older documents to the
+// current version. Writable files are migrated in place after the previous
+// content has been backed up next to them.
+func loadConfigFile(path string) (*configFile, error) {
+	cfg, pending, err := readConfig(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &configFile{
+		path:     path,
+		config:   cfg,
+		writable: isWritable(path),
+	}
+
+	// A failed in-place upgrade, e.g. on a read-only filesystem, is not
+	// fatal: the upgraded document is used and the upgrade retried next time
+	if pending != nil && f.writable {
+		_ = f.update(func(*types.Config) error { return nil })
+	}
+
+	return f, nil
+}
+
+// readConfig parses the config file at path, treating a missing or empty
+// file as an empty config, and upgrades it to the current version
+func readConfig(path string) (*types.Config, *pendingMigration, error) {
+	cfg := newConfig()
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read config file %s: %w", path, err)
+	}
+
+	doc := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("could not parse config file %s: %w", path, err)
+	}
+	if len(doc) == 0 {
+		return cfg, nil, nil
+	}
+
+	from, migrated, err := migrate(doc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	// Round-trip the raw document into the typed one
+	upgraded, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := yaml.Unmarshal(upgraded, cfg); err != nil {
+		return nil, nil, fmt.Errorf("could not parse config file %s: %w", path, err)
+	}
+	if cfg.Contexts == nil {
+		cfg.Contexts = make(map[string]*types.Context)
+	}
+
+	if migrated {
+		return cfg, &pendingMigration{from: from, data: data}, nil
+	}
+	return cfg, nil, nil
+}
+
+// update applies fn to the latest content of the file while holding its
+// lock, so concurrent ecs processes never overwrite each other's changes,
+// and then atomically replaces the file with the result
+func (f *configFile) update(fn func(cfg *types.Config) error) error {
+	lock, err := acquireLock(f.path)
+	if err != nil {
+		return err
+	}
+	defer lock.release()
+
+	cfg, pending, err := readConfig(f.path)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(cfg); err != nil {
+		return err
+	}
+
+	if pending != nil {
+		if err := backupConfigFile(f.path, pending.from, pending.data); err != nil {
+			return err
+		}
+	}
+
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(f.path, data, 0600); err != nil {
+		return err
+	}
+
+	f.config = cfg
+	return nil
+}
+
+// backupConfigFile keeps the content of a config file as it was before a
+// migration from the given version
+func backupConfigFile(path, version string, data []byte) error {
+	if version == "" {
+		version = "v0"
+	}
+	backup := fmt.Sprintf("%s.%s.bak", path, filepath.Base(version))
+	if err := writeFileAtomic(backup, data, 0600); err != nil {
+		return fmt.Errorf("could not back up config file before migration: %w", err)
+	}
+	return nil
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames
+// it into place, so readers never observe a partially written file. An
+// existing file keeps its mode, e.g. a team file shared with 0644, and perm
+// only applies to new files.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("could not create temporary config file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("could not write config file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("could not write config file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("could not write config file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("could not replace config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// isWritable reports whether the file can be written, treating a missing
+// file as writable since it is created on first write
+func isWritable(path string) bool {
+	file, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return errors.Is(err, os.ErrNotExist)
+	}
+	file.Close()
+	return true
+}
@@ -0,0 +1,50 @@
+// pkg/config/file_test.go
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileAtomicMode(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing os.FileMode
+		want     os.FileMode
+	}{
+		{"new file", 0, 0600},
+		{"shared file", 0644, 0644},
+		{"private file", 0600, 0600},
+		{"group file", 0660, 0660},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if tt.existing != 0 {
+				if err := os.WriteFile(path, []byte("old"), tt.existing); err != nil {
+					t.Fatal(err)
+				}
+				// WriteFile is subject to the umask
+				if err := os.Chmod(path, tt.existing); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := writeFileAtomic(path, []byte("new"), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if mode := info.Mode().Perm(); mode != tt.want {
+				t.Errorf("mode = %o, want %o", mode, tt.want)
+			}
+			if data, _ := os.ReadFile(path); string(data) != "new" {
+				t.Errorf("content = %q, want %q", data, "new")
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+// pkg/config/lock.go
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// lockTimeout bounds how long a write waits for another ecs process
+// holding the same config file
+const lockTimeout = 10 * time.Second
+
+// errLocked is returned by tryLockFile when another process holds the lock
+var errLocked = errors.New("file is locked")
+
+// fileLock is an exclusive advisory lock on a config file, held through a
+// separate lock file so the config file itself can be replaced by rename
+type fileLock struct {
+	file *os.File
+}
+
+// acquireLock waits until it holds the lock for the config file at path
+func acquireLock(path string) (*fileLock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("could not create config directory: %w", err)
+	}
+
+	file, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("could not open lock file: %w", err)
+	}
+
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		err := tryLockFile(file)
+		if err == nil {
+			return &fileLock{file: file}, nil
+		}
+		if !errors.Is(err, errLocked) {
+			file.Close()
+			return nil, fmt.Errorf("could not lock config file %s: %w", path, err)
+		}
+		if time.Now().After(deadline) {
+			file.Close()
+			return nil, fmt.Errorf("timed out waiting for another ecs process to release %s", path)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// release gives up the lock
+func (l *fileLock) release() error {
+	if err := unlockFile(l.file); err != nil {
+		l.file.Close()
+		return err
+	}
+	return l.file.Close()
+}
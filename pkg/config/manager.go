@@ -5,158 +5,416 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
 
-	"github.com/mitchellh/mapstructure"
-	"github.com/spf13/viper"
 	"github.com/yogendratamang48/ecs/pkg/types"
+	"gopkg.in/yaml.v2"
 )
 
+// Manager merges contexts from one or more config files. Earlier files take
+// precedence, and changes are written to the first writable file.
 type Manager struct {
-	configFile string
+	files []*configFile
 }
 
-// NewManager creates a new config manager
-func NewManager() *Manager {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		panic(fmt.Sprintf("could not get home directory: %v", err))
-	}
-
-	configDir := filepath.Join(home, ".ecs")
-	if err := os.MkdirAll(configDir, 0755); err != nil {
-		panic(fmt.Sprintf("could not create config directory: %v", err))
+// NewManager creates a new config manager for the given files, in order of
+// precedence. Files that don't exist yet are treated as empty. Without any
+// files the manager holds no contexts and contexts have to be given through
+// flags and the environment.
+func NewManager(files ...string) (*Manager, error) {
+	m := &Manager{}
+	for _, path := range files {
+		f, err := loadConfigFile(path)
+		if err != nil {
+			return nil, err
+		}
+		m.files = append(m.files, f)
 	}
 
-	configFile := filepath.Join(configDir, "config.yaml")
-
-	// Initialize Viper configuration
-	viper.SetConfigFile(configFile)
-	viper.SetConfigType("yaml")
+	return m, nil
+}
 
-	// Create config file if it doesn't exist
-	if _, err := os.Stat(configFile); os.IsNotExist(err) {
-		file, err := os.Create(configFile)
-		if err != nil {
-			panic(fmt.Sprintf("could not create config file: %v", err))
+// writeTarget returns the first writable config file
+func (m *Manager) writeTarget() (*configFile, error) {
+	if len(m.files) == 0 {
+		return nil, ErrNoConfigFile
+	}
+	for _, f := range m.files {
+		if f.writable {
+			return f, nil
 		}
-		file.Close()
+	}
+	return nil, fmt.Errorf("none of the config files are writable: %w", &ReadOnlyError{Path: m.files[0].path})
+}
 
-		viper.Set("contexts", map[string]interface{}{})
-		viper.Set("current-context", "")
+// lookup returns the first config file defining the named context
+func (m *Manager) lookup(name string) (*configFile, *types.Context, bool) {
+	for _, f := range m.files {
+		if ctx, ok := f.config.Contexts[name]; ok {
+			return f, ctx, true
+		}
+	}
+	return nil, nil, false
+}
 
-		if err := viper.WriteConfig(); err != nil {
-			panic(fmt.Sprintf("could not write initial config: %v", err))
+// shadowed returns a ReadOnlyError when a file ahead of target defines the
+// named context. Such a file comes before the first writable one, so it is
+// read-only, and a context written to target would never be used.
+func (m *Manager) shadowed(name string, target *configFile) error {
+	for _, f := range m.files {
+		if f == target {
+			return nil
+		}
+		if _, ok := f.config.Contexts[name]; ok {
+			return fmt.Errorf("cannot change context '%s': %w", name, &ReadOnlyError{Path: f.path})
 		}
 	}
+	return nil
+}
 
-	// Read the config file
-	if err := viper.ReadInConfig(); err != nil {
-		panic(fmt.Sprintf("could not read config file: %v", err))
+// currentContextShadowed returns a ReadOnlyError when a file ahead of target
+// sets a current-context other than name, which would keep taking precedence
+// over the one written to target
+func (m *Manager) currentContextShadowed(name string, target *configFile) error {
+	for _, f := range m.files {
+		if f == target {
+			return nil
+		}
+		if current := f.config.CurrentContext; current != "" && current != name {
+			return fmt.Errorf("cannot switch from current context '%s': %w", current, &ReadOnlyError{Path: f.path})
+		}
 	}
+	return nil
+}
 
-	return &Manager{
-		configFile: configFile,
+// currentContextName returns the first current-context set in the search path
+func (m *Manager) currentContextName() string {
+	for _, f := range m.files {
+		if f.config.CurrentContext != "" {
+			return f.config.CurrentContext
+		}
 	}
+	return ""
+}
+
+// Overrides holds per-invocation values that take precedence over the
+// persisted configuration without being written back to the config file
+type Overrides struct {
+	Context string
+	Cluster string
+	Region  string
+	Profile string
 }
 
 // GetContext returns the current context
 func (m *Manager) GetContext() (*types.Context, error) {
-	currentContextName := viper.GetString("current-context")
+	currentContextName := m.currentContextName()
 	if currentContextName == "" {
-		return nil, fmt.Errorf("no current context set")
+		return nil, ErrNoCurrentContext
 	}
 
-	contexts := viper.GetStringMap("contexts")
-	ctxInterface, ok := contexts[currentContextName]
+	ctx, err := m.GetContextByName(currentContextName)
+	if err != nil {
+		return nil, fmt.Errorf("current context: %w", err)
+	}
+
+	return ctx, nil
+}
+
+// GetContextByName returns a copy of the context with the given name
+func (m *Manager) GetContextByName(name string) (*types.Context, error) {
+	_, ctx, ok := m.lookup(name)
 	if !ok {
-		return nil, fmt.Errorf("current context '%s' not found", currentContextName)
+		return nil, &ContextNotFoundError{Name: name}
 	}
 
-	var ctx types.Context
-	if err := m.convertToStruct(ctxInterface, &ctx); err != nil {
+	c := *ctx
+	return &c, nil
+}
+
+// ResolveContext returns the context selected by the overrides, falling back
+// to the current context, with any cluster, region or profile override applied.
+// Without a current context, a cluster override alone makes an unnamed
+// context so that commands can run without any config file.
+func (m *Manager) ResolveContext(o Overrides) (*types.Context, error) {
+	var ctx *types.Context
+	var err error
+	switch {
+	case o.Context != "":
+		ctx, err = m.GetContextByName(o.Context)
+	case m.currentContextName() == "" && o.Cluster != "":
+		ctx = &types.Context{}
+	default:
+		ctx, err = m.GetContext()
+	}
+	if err != nil {
 		return nil, err
 	}
 
-	return &ctx, nil
+	if o.Cluster != "" {
+		ctx.Cluster = o.Cluster
+	}
+	if o.Region != "" {
+		ctx.Region = o.Region
+	}
+	if o.Profile != "" {
+		ctx.Profile = o.Profile
+	}
+
+	return ctx, nil
 }
 
 // SetContext saves a new context and sets it as current
 func (m *Manager) SetContext(ctx *types.Context) error {
-	contexts := viper.GetStringMap("contexts")
-	if contexts == nil {
-		contexts = make(map[string]interface{})
+	if err := m.ValidateContext(ctx); err != nil {
+		return err
 	}
 
-	contexts[ctx.Name] = ctx
-	viper.Set("contexts", contexts)
-	viper.Set("current-context", ctx.Name)
+	f, err := m.writeTarget()
+	if err != nil {
+		return err
+	}
+	if err := m.shadowed(ctx.Name, f); err != nil {
+		return err
+	}
 
-	return viper.WriteConfig()
+	return f.update(func(cfg *types.Config) error {
+		cfg.Contexts[ctx.Name] = ctx
+		cfg.CurrentContext = ctx.Name
+		return nil
+	})
 }
 
-// ListContexts returns all configured contexts and the current context name
+// SaveContext saves a context without changing the current context
+func (m *Manager) SaveContext(ctx *types.Context) error {
+	if err := m.ValidateContext(ctx); err != nil {
+		return err
+	}
+
+	f, err := m.writeTarget()
+	if err != nil {
+		return err
+	}
+	if err := m.shadowed(ctx.Name, f); err != nil {
+		return err
+	}
+
+	return f.update(func(cfg *types.Config) error {
+		cfg.Contexts[ctx.Name] = ctx
+		return nil
+	})
+}
+
+// ListContexts returns all configured contexts and the current context name.
+// A context defined in several files is taken from the first of them.
 func (m *Manager) ListContexts() ([]types.Context, string, error) {
 	var contextList []types.Context
-	currentContext := viper.GetString("current-context")
-
-	contexts := viper.GetStringMap("contexts")
-	for _, ctxInterface := range contexts {
-		var ctx types.Context
-		if err := m.convertToStruct(ctxInterface, &ctx); err != nil {
-			return nil, "", err
+	currentContext := m.currentContextName()
+
+	seen := make(map[string]bool)
+	for _, f := range m.files {
+		for name, ctx := range f.config.Contexts {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			contextList = append(contextList, *ctx)
 		}
-		contextList = append(contextList, ctx)
 	}
 
+	sort.Slice(contextList, func(i, j int) bool {
+		return contextList[i].Name < contextList[j].Name
+	})
+
 	return contextList, currentContext, nil
 }
 
-// Helper method to convert map[string]interface{} to Context struct
-func (m *Manager) convertToStruct(in interface{}, out *types.Context) error {
-	config := &mapstructure.DecoderConfig{
-		Metadata: nil,
-		Result:   out,
-		TagName:  "mapstructure",
+// GetConfigFile returns the path to the config file changes are written to,
+// or an empty string when there is none
+func (m *Manager) GetConfigFile() string {
+	if f, err := m.writeTarget(); err == nil {
+		return f.path
 	}
-	decoder, err := mapstructure.NewDecoder(config)
+	return ""
+}
+
+// CacheDir returns the directory for cached credentials, $HOME/.ecs/cache.
+// It doesn't follow the config files, since a file shared by a team must not
+// put one user's credentials where the others can replace them. An empty
+// string means caching is unavailable.
+func (m *Manager) CacheDir() string {
+	home, err := os.UserHomeDir()
 	if err != nil {
-		return err
+		return ""
 	}
-	return decoder.Decode(in)
+	return filepath.Join(home, ".ecs", "cache")
 }
 
-// GetConfigFile returns the path to the config file
-func (m *Manager) GetConfigFile() string {
-	return m.configFile
+// GetConfigFiles returns the paths of all merged config files
+func (m *Manager) GetConfigFiles() []string {
+	var paths []string
+	for _, f := range m.files {
+		paths = append(paths, f.path)
+	}
+	return paths
 }
 
 // DeleteContext removes a context
 func (m *Manager) DeleteContext(name string) error {
-	contexts := viper.GetStringMap("contexts")
-	if _, exists := contexts[name]; !exists {
-		return fmt.Errorf("context '%s' not found", name)
+	f, err := m.writableContext(name)
+	if err != nil {
+		return err
 	}
 
-	delete(contexts, name)
-	viper.Set("contexts", contexts)
+	return f.update(func(cfg *types.Config) error {
+		if _, ok := cfg.Contexts[name]; !ok {
+			return &ContextNotFoundError{Name: name}
+		}
+		delete(cfg.Contexts, name)
 
-	// If we're deleting the current context, clear it
-	if viper.GetString("current-context") == name {
-		viper.Set("current-context", "")
+		// If we're deleting the current context, clear it
+		if cfg.CurrentContext == name {
+			cfg.CurrentContext = ""
+		}
+		return nil
+	})
+}
+
+// writableContext returns the file defining the named context, which must
+// be writable for the context to be changed in place
+func (m *Manager) writableContext(name string) (*configFile, error) {
+	f, _, ok := m.lookup(name)
+	if !ok {
+		return nil, &ContextNotFoundError{Name: name}
 	}
+	if !f.writable {
+		return nil, fmt.Errorf("cannot change context '%s': %w", name, &ReadOnlyError{Path: f.path})
+	}
+	return f, nil
+}
 
-	return viper.WriteConfig()
+// UpdateContext changes fields of an existing context in a single write.
+// Fields are addressed by their config keys, e.g. "region" or "endpoints.ecs".
+func (m *Manager) UpdateContext(name string, fields map[string]string) error {
+	f, err := m.writableContext(name)
+	if err != nil {
+		return err
+	}
+
+	return f.update(func(cfg *types.Config) error {
+		ctx, ok := cfg.Contexts[name]
+		if !ok {
+			return &ContextNotFoundError{Name: name}
+		}
+
+		updated := *ctx
+		for key, value := range fields {
+			if key == "name" {
+				return fmt.Errorf("use rename-context to change the name of a context")
+			}
+			if err := setField(reflect.ValueOf(&updated).Elem(), strings.Split(key, "."), value); err != nil {
+				return fmt.Errorf("contexts.%s.%s: %w", name, key, err)
+			}
+		}
+
+		if err := m.ValidateContext(&updated); err != nil {
+			return err
+		}
+		cfg.Contexts[name] = &updated
+		return nil
+	})
+}
+
+// RenameContext renames a context, following it with current-context
+func (m *Manager) RenameContext(oldName, newName string) error {
+	if _, _, exists := m.lookup(newName); exists {
+		return fmt.Errorf("context '%s' already exists", newName)
+	}
+
+	f, err := m.writableContext(oldName)
+	if err != nil {
+		return err
+	}
+
+	return f.update(func(cfg *types.Config) error {
+		ctx, ok := cfg.Contexts[oldName]
+		if !ok {
+			return &ContextNotFoundError{Name: oldName}
+		}
+		if _, exists := cfg.Contexts[newName]; exists {
+			return fmt.Errorf("context '%s' already exists", newName)
+		}
+
+		renamed := *ctx
+		renamed.Name = newName
+		if err := m.ValidateContext(&renamed); err != nil {
+			return err
+		}
+
+		delete(cfg.Contexts, oldName)
+		cfg.Contexts[newName] = &renamed
+		if cfg.CurrentContext == oldName {
+			cfg.CurrentContext = newName
+		}
+		return nil
+	})
+}
+
+// CopyContext saves a copy of a context under a new name
+func (m *Manager) CopyContext(srcName, dstName string) error {
+	if _, _, exists := m.lookup(dstName); exists {
+		return fmt.Errorf("context '%s' already exists", dstName)
+	}
+
+	ctx, err := m.GetContextByName(srcName)
+	if err != nil {
+		return err
+	}
+	ctx.Name = dstName
+
+	return m.SaveContext(ctx)
+}
+
+// Set changes a single setting addressed by its dotted config key, such as
+// current-context, contexts.NAME.region, defaults.output or aliases.NAME
+func (m *Manager) Set(key, value string) error {
+	parts := strings.Split(key, ".")
+	switch {
+	case key == "current-context":
+		return m.UseContext(value)
+	case parts[0] == "defaults" && len(parts) >= 2:
+		return m.SetDefault(strings.Join(parts[1:], "."), value)
+	case parts[0] == "aliases" && len(parts) == 2:
+		return m.SetAlias(parts[1], value)
+	case parts[0] == "contexts" && len(parts) >= 3:
+		return m.UpdateContext(parts[1], map[string]string{
+			strings.Join(parts[2:], "."): value,
+		})
+	default:
+		return fmt.Errorf("unknown config key %q", key)
+	}
 }
 
 // UseContext sets the current context
 func (m *Manager) UseContext(name string) error {
-	contexts := viper.GetStringMap("contexts")
-	if _, exists := contexts[name]; !exists {
-		return fmt.Errorf("context '%s' not found", name)
+	if _, _, ok := m.lookup(name); !ok {
+		return &ContextNotFoundError{Name: name}
 	}
 
-	viper.Set("current-context", name)
-	return viper.WriteConfig()
+	f, err := m.writeTarget()
+	if err != nil {
+		return err
+	}
+	if err := m.currentContextShadowed(name, f); err != nil {
+		return err
+	}
+
+	return f.update(func(cfg *types.Config) error {
+		cfg.CurrentContext = name
+		return nil
+	})
 }
 
 // ValidateContext checks if a context is valid
@@ -167,27 +425,60 @@ func (m *Manager) ValidateContext(ctx *types.Context) error {
 	if ctx.Cluster == "" {
 		return fmt.Errorf("cluster name cannot be empty")
 	}
+	switch ctx.Credentials.Source {
+	case "", types.CredentialsFromProfile, types.CredentialsFromEnv, types.CredentialsFromWebIdentity:
+	case types.CredentialsFromProcess:
+		if ctx.Credentials.Process == "" {
+			return fmt.Errorf("credentials source %q needs a command", ctx.Credentials.Source)
+		}
+	default:
+		return fmt.Errorf("unknown credentials source %q (profile|env|process|web-identity)", ctx.Credentials.Source)
+	}
+	if ctx.SSO != (types.SSO{}) {
+		if ctx.Credentials.Source != "" && ctx.Credentials.Source != types.CredentialsFromProfile {
+			return fmt.Errorf("SSO settings cannot be combined with credentials source %q", ctx.Credentials.Source)
+		}
+		if ctx.SSO.StartURL == "" || ctx.SSO.Region == "" || ctx.SSO.AccountID == "" || ctx.SSO.RoleName == "" {
+			return fmt.Errorf("SSO settings need a start URL, region, account ID and role name")
+		}
+	}
+	switch ctx.Retry.Mode {
+	case "", types.RetryModeStandard, types.RetryModeAdaptive:
+	default:
+		return fmt.Errorf("unknown retry mode %q (standard|adaptive)", ctx.Retry.Mode)
+	}
+	if err := validateDefaults(ctx.Defaults); err != nil {
+		return err
+	}
+	if ctx.Retry.MaxAttempts < 0 {
+		return fmt.Errorf("retry max attempts cannot be negative")
+	}
+	if ctx.Retry.RateLimit < 0 || ctx.Retry.Burst < 0 {
+		return fmt.Errorf("rate limit and burst cannot be negative")
+	}
 	return nil
 }
 
+// ViewConfig renders the merged configuration as a config document
 func (m *Manager) ViewConfig() (string, error) {
-	contexts := viper.GetStringMap("contexts")
-	currentContext := viper.GetString("current-context")
-
-	// Format the configuration as YAML
-	output := fmt.Sprintf("Current-context: %s\n\nContexts:\n", currentContext)
-
-	for name, ctxInterface := range contexts {
-		var ctx types.Context
-		if err := m.convertToStruct(ctxInterface, &ctx); err != nil {
-			return "", err
-		}
+	contexts, currentContext, err := m.ListContexts()
+	if err != nil {
+		return "", err
+	}
 
-		output += fmt.Sprintf("\n%s:\n", name)
-		output += fmt.Sprintf("  cluster: %s\n", ctx.Cluster)
-		output += fmt.Sprintf("  profile: %s\n", ctx.Profile)
-		output += fmt.Sprintf("  region: %s\n", ctx.Region)
+	merged := newConfig()
+	merged.CurrentContext = currentContext
+	if aliases := m.Aliases(); len(aliases) > 0 {
+		merged.Aliases = aliases
+	}
+	merged.Defaults = m.Defaults(nil)
+	for i := range contexts {
+		merged.Contexts[contexts[i].Name] = &contexts[i]
 	}
 
-	return output, nil
+	data, err := yaml.Marshal(merged)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
 }
@@ -0,0 +1,63 @@
+// pkg/config/migrate.go
+package config
+
+import "fmt"
+
+const (
+	// APIVersion is the config document version written by this release
+	APIVersion = "ecs-cli/v1"
+	// Kind identifies an ECS CLI config document
+	Kind = "Config"
+)
+
+// migration upgrades a raw config document by one version
+type migration struct {
+	to    string
+	apply func(doc map[string]interface{}) error
+}
+
+// migrations are keyed by the apiVersion they upgrade from. Documents
+// written before versioning was introduced have no apiVersion.
+var migrations = map[string]migration{
+	"": {to: "ecs-cli/v1", apply: migrateV0ToV1},
+}
+
+// migrate upgrades doc in place to APIVersion, returning the version it
+// started from and whether anything changed
+func migrate(doc map[string]interface{}) (string, bool, error) {
+	from, _ := doc["apiVersion"].(string)
+	version := from
+	for version != APIVersion {
+		m, ok := migrations[version]
+		if !ok {
+			return from, false, fmt.Errorf("unsupported config apiVersion %q, this release supports %q", version, APIVersion)
+		}
+		if err := m.apply(doc); err != nil {
+			return from, false, fmt.Errorf("failed to migrate config from %q to %q: %w", version, m.to, err)
+		}
+		doc["apiVersion"] = m.to
+		version = m.to
+	}
+	return from, from != APIVersion, nil
+}
+
+// migrateV0ToV1 adds the kind marker to the untyped viper layout and makes
+// sure every context carries its own name
+func migrateV0ToV1(doc map[string]interface{}) error {
+	doc["kind"] = Kind
+
+	contexts, ok := doc["contexts"].(map[interface{}]interface{})
+	if !ok {
+		return nil
+	}
+	for name, ctx := range contexts {
+		fields, ok := ctx.(map[interface{}]interface{})
+		if !ok {
+			return fmt.Errorf("context %v is not a mapping", name)
+		}
+		if n, _ := fields["name"].(string); n == "" {
+			fields["name"] = fmt.Sprint(name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+// pkg/config/migrate_test.go
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestMigrate(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       string
+		from     string
+		migrated bool
+		want     string
+	}{
+		{
+			name:     "unversioned",
+			in:       "current-context: dev\ncontexts:\n  dev:\n    cluster: c1\n  prod:\n    name: prod\n    cluster: c2\n",
+			from:     "",
+			migrated: true,
+			want:     "apiVersion: ecs-cli/v1\nkind: Config\ncurrent-context: dev\ncontexts:\n  dev:\n    name: dev\n    cluster: c1\n  prod:\n    name: prod\n    cluster: c2\n",
+		},
+		{
+			name:     "unversioned without contexts",
+			in:       "current-context: dev\n",
+			from:     "",
+			migrated: true,
+			want:     "apiVersion: ecs-cli/v1\nkind: Config\ncurrent-context: dev\n",
+		},
+		{
+			name:     "current",
+			in:       "apiVersion: ecs-cli/v1\nkind: Config\ncontexts:\n  dev:\n    name: dev\n",
+			from:     APIVersion,
+			migrated: false,
+			want:     "apiVersion: ecs-cli/v1\nkind: Config\ncontexts:\n  dev:\n    name: dev\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := parseDoc(t, tt.in)
+			from, migrated, err := migrate(doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if from != tt.from || migrated != tt.migrated {
+				t.Errorf("migrate() = %q, %v, want %q, %v", from, migrated, tt.from, tt.migrated)
+			}
+			if want := parseDoc(t, tt.want); !reflect.DeepEqual(doc, want) {
+				t.Errorf("migrated document = %v, want %v", doc, want)
+			}
+		})
+	}
+}
+
+func TestMigrateErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"newer version", "apiVersion: ecs-cli/v9\n", `unsupported config apiVersion "ecs-cli/v9"`},
+		{"context not a mapping", "contexts:\n  dev: c1\n", "context dev is not a mapping"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := migrate(parseDoc(t, tt.in))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("migrate() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMigrateFileInPlace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	original := "current-context: dev\ncontexts:\n  dev:\n    cluster: c1\n    region: us-east-1\n"
+	if err := os.WriteFile(path, []byte(original), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := NewManager(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err := m.GetContext()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ctx.Name != "dev" || ctx.Cluster != "c1" || ctx.Region != "us-east-1" {
+		t.Errorf("migrated context = %+v", ctx)
+	}
+
+	backup, err := os.ReadFile(path + ".v0.bak")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(backup) != original {
+		t.Errorf("backup = %q, want the original content", backup)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := parseDoc(t, string(data))
+	if doc["apiVersion"] != APIVersion || doc["kind"] != Kind {
+		t.Errorf("rewritten file is not upgraded:\n%s", data)
+	}
+}
+
+func parseDoc(t *testing.T, s string) map[string]interface{} {
+	t.Helper()
+	doc := make(map[string]interface{})
+	if err := yaml.Unmarshal([]byte(s), &doc); err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
@@ -0,0 +1,66 @@
+// pkg/config/paths.go
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// ConfigEnvVar lists the config files to merge, separated like PATH
+const ConfigEnvVar = "ECS_CONFIG"
+
+// DefaultConfigFile returns the path of the personal config file. An
+// existing $HOME/.ecs/config.yaml keeps being used; otherwise the file lives
+// in $XDG_CONFIG_HOME/ecs when XDG_CONFIG_HOME is set, and in $HOME/.ecs when
+// it is not.
+func DefaultConfigFile() (string, error) {
+	home, homeErr := os.UserHomeDir()
+
+	legacy := ""
+	if homeErr == nil {
+		legacy = filepath.Join(home, ".ecs", "config.yaml")
+		if _, err := os.Stat(legacy); err == nil {
+			return legacy, nil
+		}
+	}
+
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); filepath.IsAbs(xdg) {
+		return filepath.Join(xdg, "ecs", "config.yaml"), nil
+	}
+
+	if homeErr != nil {
+		return "", ErrNoConfigDir
+	}
+	return legacy, nil
+}
+
+// SearchPath returns the config files to merge, in order of precedence.
+// An explicit list (from --config) wins over ECS_CONFIG, which wins over
+// the default config file. When no default location can be determined the
+// list is empty, and the CLI runs from flags and the environment alone.
+func SearchPath(explicit string) ([]string, error) {
+	list := explicit
+	if list == "" {
+		list = os.Getenv(ConfigEnvVar)
+	}
+
+	var files []string
+	for _, file := range filepath.SplitList(list) {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	if len(files) > 0 {
+		return files, nil
+	}
+
+	file, err := DefaultConfigFile()
+	if errors.Is(err, ErrNoConfigDir) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []string{file}, nil
+}
@@ -0,0 +1,319 @@
+// pkg/fake/backend.go
+package fake
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ecstypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+const (
+	defaultRegion  = "us-east-1"
+	defaultAccount = "000000000000"
+
+	// initialTaskAge is how long before the backend was created the tasks of
+	// a fixture were started
+	initialTaskAge = 5 * time.Minute
+)
+
+// Backend is an in-memory ECS, CloudWatch Logs and SSM backend. It serves the
+// calls the CLI makes from clusters described by a fixture, so commands can
+// be run and demonstrated without an AWS account. State only lives as long
+// as the Backend: scaling a service or stopping a task is visible to later
+// calls on the same Backend only.
+type Backend struct {
+	mu sync.Mutex
+
+	region  string
+	account string
+
+	clusters map[string]*cluster
+	taskDefs map[string][]ServiceFixture
+	logs     map[string][]logEvent
+
+	// template, if set, is used to create clusters that are not in the
+	// fixture on first use
+	template *ClusterFixture
+
+	now func() time.Time
+}
+
+type cluster struct {
+	name      string
+	services  []*service
+	tasks     []*task
+	createdAt time.Time
+	seq       int
+}
+
+type service struct {
+	fixture   ServiceFixture
+	taskDef   string
+	desired   int32
+	createdAt time.Time
+	events    []ecstypes.ServiceEvent
+}
+
+type task struct {
+	id            string
+	service       *service
+	lastStatus    string
+	desiredStatus string
+	createdAt     time.Time
+	startedAt     time.Time
+	stoppedAt     time.Time
+	stoppedReason string
+}
+
+type logEvent struct {
+	timestamp int64
+	message   string
+}
+
+// New returns a backend serving the clusters of the fixture
+func New(f *Fixture) *Backend {
+	b := &Backend{
+		region:   f.Region,
+		account:  defaultAccount,
+		clusters: make(map[string]*cluster),
+		taskDefs: make(map[string][]ServiceFixture),
+		logs:     make(map[string][]logEvent),
+		now:      time.Now,
+	}
+	if b.region == "" {
+		b.region = defaultRegion
+	}
+
+	for _, c := range f.Clusters {
+		b.addCluster(c.Name, c)
+	}
+	return b
+}
+
+// NewDemo returns a backend with a "demo" cluster. Any other cluster name
+// gets a copy of the demo cluster on first use, so every context works.
+func NewDemo() *Backend {
+	b := New(&Fixture{Clusters: []ClusterFixture{demoCluster}})
+	b.template = &demoCluster
+	return b
+}
+
+// addCluster creates a cluster and starts the tasks of its services. The
+// caller must hold b.mu, or have exclusive access to b.
+func (b *Backend) addCluster(name string, f ClusterFixture) *cluster {
+	now := b.now()
+	c := &cluster{
+		name:      name,
+		createdAt: now.Add(-30 * 24 * time.Hour),
+	}
+	b.clusters[name] = c
+
+	for _, sf := range f.Services {
+		if sf.LaunchType == "" {
+			sf.LaunchType = string(ecstypes.LaunchTypeFargate)
+		}
+		if sf.Cpu == "" {
+			sf.Cpu = "256"
+		}
+		if sf.Memory == "" {
+			sf.Memory = "512"
+		}
+
+		s := &service{
+			fixture:   sf,
+			taskDef:   b.registerTaskDef(sf),
+			desired:   sf.DesiredCount,
+			createdAt: now.Add(-7 * 24 * time.Hour),
+		}
+		c.services = append(c.services, s)
+		b.reconcile(c, s, now.Add(-initialTaskAge))
+	}
+	return c
+}
+
+// registerTaskDef registers a new revision of the task definition family
+// named after the service, and returns its ARN
+func (b *Backend) registerTaskDef(sf ServiceFixture) string {
+	b.taskDefs[sf.Name] = append(b.taskDefs[sf.Name], sf)
+	return b.taskDefArn(sf.Name, len(b.taskDefs[sf.Name]))
+}
+
+// cluster returns the named cluster, accepting a name or an ARN and
+// defaulting to "default" like ECS does
+func (b *Backend) cluster(name *string) (*cluster, error) {
+	n := aws.ToString(name)
+	if n == "" {
+		n = "default"
+	}
+	n = lastPart(n)
+
+	if c, ok := b.clusters[n]; ok {
+		return c, nil
+	}
+	if b.template != nil {
+		return b.addCluster(n, *b.template), nil
+	}
+	return nil, &ecstypes.ClusterNotFoundException{Message: aws.String("Cluster not found.")}
+}
+
+// service returns the named service of the cluster, accepting a name or an
+// ARN
+func (c *cluster) service(name string) *service {
+	name = lastPart(name)
+	for _, s := range c.services {
+		if s.fixture.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// task returns the task with the given ID or ARN
+func (c *cluster) task(id string) *task {
+	id = lastPart(id)
+	for _, t := range c.tasks {
+		if t.id == id {
+			return t
+		}
+	}
+	return nil
+}
+
+// running returns the tasks of the service that have not been stopped
+func (c *cluster) running(s *service) []*task {
+	var tasks []*task
+	for _, t := range c.tasks {
+		if t.service == s && t.desiredStatus == string(ecstypes.DesiredStatusRunning) {
+			tasks = append(tasks, t)
+		}
+	}
+	return tasks
+}
+
+// reconcile starts or stops tasks until the service runs its desired count,
+// recording service events like the ECS scheduler does
+func (b *Backend) reconcile(c *cluster, s *service, at time.Time) {
+	running := c.running(s)
+	name := s.fixture.Name
+
+	switch {
+	case int32(len(running)) < s.desired:
+		var ids []string
+		for i := int32(len(running)); i < s.desired; i++ {
+			t := b.startTask(c, s, at)
+			ids = append(ids, "(task "+t.id+")")
+		}
+		b.addEvent(s, at, fmt.Sprintf("(service %s) has started %d tasks: %s.", name, len(ids), strings.Join(ids, " ")))
+
+	case int32(len(running)) > s.desired:
+		// Stop the newest tasks first
+		var ids []string
+		for _, t := range running[s.desired:] {
+			b.stopTask(t, at, "Scaling activity initiated by (deployment ecs-svc)")
+			ids = append(ids, "(task "+t.id+")")
+		}
+		b.addEvent(s, at, fmt.Sprintf("(service %s) has stopped %d running tasks: %s.", name, len(ids), strings.Join(ids, " ")))
+	}
+
+	b.addEvent(s, at, fmt.Sprintf("(service %s) has reached a steady state.", name))
+}
+
+// startTask starts a task of the service and records its startup logs
+func (b *Backend) startTask(c *cluster, s *service, at time.Time) *task {
+	c.seq++
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s/%s/%d", c.name, s.fixture.Name, c.seq)))
+	t := &task{
+		id:            hex.EncodeToString(sum[:])[:32],
+		service:       s,
+		lastStatus:    string(ecstypes.DesiredStatusRunning),
+		desiredStatus: string(ecstypes.DesiredStatusRunning),
+		createdAt:     at.Add(-20 * time.Second),
+		startedAt:     at,
+	}
+	c.tasks = append(c.tasks, t)
+
+	for _, container := range s.fixture.Containers {
+		key := logKey(logGroup(s.fixture.Name), logStream(container.Name, t.id))
+		for i, line := range container.Logs {
+			b.logs[key] = append(b.logs[key], logEvent{
+				timestamp: at.Add(time.Duration(i) * 15 * time.Second).UnixMilli(),
+				message:   line,
+			})
+		}
+	}
+	return t
+}
+
+func (b *Backend) stopTask(t *task, at time.Time, reason string) {
+	t.lastStatus = string(ecstypes.DesiredStatusStopped)
+	t.desiredStatus = string(ecstypes.DesiredStatusStopped)
+	t.stoppedAt = at
+	t.stoppedReason = reason
+}
+
+// addEvent records a service event, newest first like ECS returns them
+func (b *Backend) addEvent(s *service, at time.Time, message string) {
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s/%d/%s", s.fixture.Name, len(s.events), message)))
+	event := ecstypes.ServiceEvent{
+		Id:        aws.String(hex.EncodeToString(sum[:])[:36]),
+		CreatedAt: aws.Time(at),
+		Message:   aws.String(message),
+	}
+	s.events = append([]ecstypes.ServiceEvent{event}, s.events...)
+}
+
+func (b *Backend) clusterNames() []string {
+	names := make([]string, 0, len(b.clusters))
+	for name := range b.clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (b *Backend) arn(service, resource string) string {
+	return fmt.Sprintf("arn:aws:%s:%s:%s:%s", service, b.region, b.account, resource)
+}
+
+func (b *Backend) clusterArn(c *cluster) string {
+	return b.arn("ecs", "cluster/"+c.name)
+}
+
+func (b *Backend) serviceArn(c *cluster, s *service) string {
+	return b.arn("ecs", "service/"+c.name+"/"+s.fixture.Name)
+}
+
+func (b *Backend) taskArn(c *cluster, t *task) string {
+	return b.arn("ecs", "task/"+c.name+"/"+t.id)
+}
+
+func (b *Backend) taskDefArn(family string, revision int) string {
+	return b.arn("ecs", fmt.Sprintf("task-definition/%s:%d", family, revision))
+}
+
+func logGroup(service string) string {
+	return "/ecs/" + service
+}
+
+// logStream returns the stream name of the awslogs driver with the "ecs"
+// stream prefix
+func logStream(container, taskID string) string {
+	return "ecs/" + container + "/" + taskID
+}
+
+func logKey(group, stream string) string {
+	return group + "\x00" + stream
+}
+
+// lastPart returns the part of an ARN after the last slash, or the name
+// itself
+func lastPart(nameOrArn string) string {
+	return nameOrArn[strings.LastIndex(nameOrArn, "/")+1:]
+}
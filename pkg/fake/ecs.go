@@ -0,0 +1,397 @@
+// pkg/fake/ecs.go
+package fake
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	ecstypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+// ListClusters returns the ARNs of the clusters created so far
+func (b *Backend) ListClusters(ctx context.Context, params *ecs.ListClustersInput, optFns ...func(*ecs.Options)) (*ecs.ListClustersOutput, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var arns []string
+	for _, name := range b.clusterNames() {
+		arns = append(arns, b.clusterArn(b.clusters[name]))
+	}
+
+	page, next, err := paginate(arns, params.NextToken, params.MaxResults, 100)
+	if err != nil {
+		return nil, err
+	}
+	return &ecs.ListClustersOutput{ClusterArns: page, NextToken: next}, nil
+}
+
+// DescribeClusters describes clusters. Unknown clusters are reported as
+// failures rather than errors, like ECS does.
+func (b *Backend) DescribeClusters(ctx context.Context, params *ecs.DescribeClustersInput, optFns ...func(*ecs.Options)) (*ecs.DescribeClustersOutput, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	names := params.Clusters
+	if len(names) == 0 {
+		names = []string{"default"}
+	}
+
+	out := &ecs.DescribeClustersOutput{}
+	for _, name := range names {
+		c, err := b.cluster(aws.String(name))
+		if err != nil {
+			out.Failures = append(out.Failures, ecstypes.Failure{
+				Arn:    aws.String(b.arn("ecs", "cluster/"+lastPart(name))),
+				Reason: aws.String("MISSING"),
+			})
+			continue
+		}
+
+		var running int32
+		for _, t := range c.tasks {
+			if t.lastStatus == string(ecstypes.DesiredStatusRunning) {
+				running++
+			}
+		}
+		out.Clusters = append(out.Clusters, ecstypes.Cluster{
+			ClusterArn:          aws.String(b.clusterArn(c)),
+			ClusterName:         aws.String(c.name),
+			Status:              aws.String("ACTIVE"),
+			ActiveServicesCount: int32(len(c.services)),
+			RunningTasksCount:   running,
+		})
+	}
+	return out, nil
+}
+
+// ListServices returns the ARNs of the services of a cluster
+func (b *Backend) ListServices(ctx context.Context, params *ecs.ListServicesInput, optFns ...func(*ecs.Options)) (*ecs.ListServicesOutput, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, err := b.cluster(params.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	var arns []string
+	for _, s := range c.services {
+		if params.LaunchType != "" && string(params.LaunchType) != s.fixture.LaunchType {
+			continue
+		}
+		arns = append(arns, b.serviceArn(c, s))
+	}
+
+	page, next, err := paginate(arns, params.NextToken, params.MaxResults, 10)
+	if err != nil {
+		return nil, err
+	}
+	return &ecs.ListServicesOutput{ServiceArns: page, NextToken: next}, nil
+}
+
+// DescribeServices describes up to 10 services, given by name or ARN
+func (b *Backend) DescribeServices(ctx context.Context, params *ecs.DescribeServicesInput, optFns ...func(*ecs.Options)) (*ecs.DescribeServicesOutput, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(params.Services) == 0 || len(params.Services) > 10 {
+		return nil, invalidParameter("Services must contain between 1 and 10 services.")
+	}
+
+	c, err := b.cluster(params.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &ecs.DescribeServicesOutput{}
+	for _, name := range params.Services {
+		s := c.service(name)
+		if s == nil {
+			out.Failures = append(out.Failures, ecstypes.Failure{
+				Arn:    aws.String(b.arn("ecs", "service/"+c.name+"/"+lastPart(name))),
+				Reason: aws.String("MISSING"),
+			})
+			continue
+		}
+		out.Services = append(out.Services, b.describeService(c, s))
+	}
+	return out, nil
+}
+
+func (b *Backend) describeService(c *cluster, s *service) ecstypes.Service {
+	return ecstypes.Service{
+		ServiceArn:     aws.String(b.serviceArn(c, s)),
+		ServiceName:    aws.String(s.fixture.Name),
+		ClusterArn:     aws.String(b.clusterArn(c)),
+		Status:         aws.String("ACTIVE"),
+		TaskDefinition: aws.String(s.taskDef),
+		DesiredCount:   s.desired,
+		RunningCount:   int32(len(c.running(s))),
+		LaunchType:     ecstypes.LaunchType(s.fixture.LaunchType),
+		CreatedAt:      aws.Time(s.createdAt),
+		NetworkConfiguration: &ecstypes.NetworkConfiguration{
+			AwsvpcConfiguration: &ecstypes.AwsVpcConfiguration{
+				Subnets:        []string{"subnet-0a1b2c3d4e5f60001", "subnet-0a1b2c3d4e5f60002"},
+				SecurityGroups: []string{"sg-0a1b2c3d4e5f60001"},
+				AssignPublicIp: ecstypes.AssignPublicIpDisabled,
+			},
+		},
+		Events: append([]ecstypes.ServiceEvent(nil), s.events...),
+	}
+}
+
+// UpdateService changes the desired count of a service, starting or stopping
+// tasks right away
+func (b *Backend) UpdateService(ctx context.Context, params *ecs.UpdateServiceInput, optFns ...func(*ecs.Options)) (*ecs.UpdateServiceOutput, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, err := b.cluster(params.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	s := c.service(aws.ToString(params.Service))
+	if s == nil {
+		return nil, &ecstypes.ServiceNotFoundException{Message: aws.String("Service not found.")}
+	}
+
+	if params.DesiredCount != nil {
+		if *params.DesiredCount < 0 {
+			return nil, invalidParameter("Desired count cannot be negative.")
+		}
+		s.desired = *params.DesiredCount
+		b.reconcile(c, s, b.now())
+	}
+	svc := b.describeService(c, s)
+	return &ecs.UpdateServiceOutput{Service: &svc}, nil
+}
+
+// ListTasks returns the ARNs of the tasks of a cluster, by default those
+// that are desired to be running
+func (b *Backend) ListTasks(ctx context.Context, params *ecs.ListTasksInput, optFns ...func(*ecs.Options)) (*ecs.ListTasksOutput, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, err := b.cluster(params.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	desired := string(params.DesiredStatus)
+	if desired == "" {
+		desired = string(ecstypes.DesiredStatusRunning)
+	}
+
+	var svc *service
+	if params.ServiceName != nil {
+		if svc = c.service(*params.ServiceName); svc == nil {
+			return nil, &ecstypes.ServiceNotFoundException{Message: aws.String("Service not found.")}
+		}
+	}
+
+	var arns []string
+	for _, t := range c.tasks {
+		if t.desiredStatus != desired || (svc != nil && t.service != svc) {
+			continue
+		}
+		if params.Family != nil && t.service.fixture.Name != *params.Family {
+			continue
+		}
+		arns = append(arns, b.taskArn(c, t))
+	}
+
+	page, next, err := paginate(arns, params.NextToken, params.MaxResults, 100)
+	if err != nil {
+		return nil, err
+	}
+	return &ecs.ListTasksOutput{TaskArns: page, NextToken: next}, nil
+}
+
+// DescribeTasks describes up to 100 tasks, given by ID or ARN. Stopped tasks
+// remain describable.
+func (b *Backend) DescribeTasks(ctx context.Context, params *ecs.DescribeTasksInput, optFns ...func(*ecs.Options)) (*ecs.DescribeTasksOutput, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(params.Tasks) == 0 || len(params.Tasks) > 100 {
+		return nil, invalidParameter("Tasks must contain between 1 and 100 tasks.")
+	}
+
+	c, err := b.cluster(params.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &ecs.DescribeTasksOutput{}
+	for _, id := range params.Tasks {
+		t := c.task(id)
+		if t == nil {
+			out.Failures = append(out.Failures, ecstypes.Failure{
+				Arn:    aws.String(b.arn("ecs", "task/"+c.name+"/"+lastPart(id))),
+				Reason: aws.String("MISSING"),
+			})
+			continue
+		}
+		out.Tasks = append(out.Tasks, b.describeTask(c, t))
+	}
+	return out, nil
+}
+
+func (b *Backend) describeTask(c *cluster, t *task) ecstypes.Task {
+	sf := t.service.fixture
+	taskArn := b.taskArn(c, t)
+
+	out := ecstypes.Task{
+		TaskArn:           aws.String(taskArn),
+		ClusterArn:        aws.String(b.clusterArn(c)),
+		TaskDefinitionArn: aws.String(t.service.taskDef),
+		LastStatus:        aws.String(t.lastStatus),
+		DesiredStatus:     aws.String(t.desiredStatus),
+		Group:             aws.String("service:" + sf.Name),
+		Cpu:               aws.String(sf.Cpu),
+		Memory:            aws.String(sf.Memory),
+		LaunchType:        ecstypes.LaunchType(sf.LaunchType),
+		CreatedAt:         aws.Time(t.createdAt),
+		StartedAt:         aws.Time(t.startedAt),
+	}
+	if !t.stoppedAt.IsZero() {
+		out.StoppedAt = aws.Time(t.stoppedAt)
+		out.StoppedReason = aws.String(t.stoppedReason)
+	}
+
+	for i, container := range sf.Containers {
+		out.Containers = append(out.Containers, ecstypes.Container{
+			Name:       aws.String(container.Name),
+			Image:      aws.String(container.Image),
+			LastStatus: aws.String(t.lastStatus),
+			TaskArn:    aws.String(taskArn),
+			RuntimeId:  aws.String(fmt.Sprintf("%s-%d", t.id, i)),
+		})
+	}
+	return out
+}
+
+// StopTask stops a task. If it belongs to a service, a replacement is
+// started.
+func (b *Backend) StopTask(ctx context.Context, params *ecs.StopTaskInput, optFns ...func(*ecs.Options)) (*ecs.StopTaskOutput, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, err := b.cluster(params.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	t := c.task(aws.ToString(params.Task))
+	if t == nil {
+		return nil, invalidParameter("The referenced task was not found.")
+	}
+
+	if t.desiredStatus != string(ecstypes.DesiredStatusStopped) {
+		now := b.now()
+		reason := aws.ToString(params.Reason)
+		if reason == "" {
+			reason = "Task stopped by user"
+		}
+		b.stopTask(t, now, reason)
+		b.reconcile(c, t.service, now)
+	}
+	stopped := b.describeTask(c, t)
+	return &ecs.StopTaskOutput{Task: &stopped}, nil
+}
+
+// DescribeTaskDefinition describes a task definition given by family,
+// family:revision or ARN. Every container logs with the awslogs driver.
+func (b *Backend) DescribeTaskDefinition(ctx context.Context, params *ecs.DescribeTaskDefinitionInput, optFns ...func(*ecs.Options)) (*ecs.DescribeTaskDefinitionOutput, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	family, revision := lastPart(aws.ToString(params.TaskDefinition)), 0
+	if i := strings.LastIndex(family, ":"); i >= 0 {
+		var err error
+		if revision, err = strconv.Atoi(family[i+1:]); err != nil {
+			return nil, invalidParameter("Invalid revision number.")
+		}
+		family = family[:i]
+	}
+
+	revisions := b.taskDefs[family]
+	if revision == 0 {
+		revision = len(revisions)
+	}
+	if revision < 1 || revision > len(revisions) {
+		return nil, &ecstypes.ClientException{Message: aws.String("Unable to describe task definition.")}
+	}
+	sf := revisions[revision-1]
+
+	def := &ecstypes.TaskDefinition{
+		TaskDefinitionArn:       aws.String(b.taskDefArn(family, revision)),
+		Family:                  aws.String(family),
+		Revision:                int32(revision),
+		Status:                  ecstypes.TaskDefinitionStatusActive,
+		Cpu:                     aws.String(sf.Cpu),
+		Memory:                  aws.String(sf.Memory),
+		NetworkMode:             ecstypes.NetworkModeAwsvpc,
+		RequiresCompatibilities: []ecstypes.Compatibility{ecstypes.Compatibility(sf.LaunchType)},
+	}
+	for _, container := range sf.Containers {
+		def.ContainerDefinitions = append(def.ContainerDefinitions, ecstypes.ContainerDefinition{
+			Name:      aws.String(container.Name),
+			Image:     aws.String(container.Image),
+			Essential: aws.Bool(true),
+			LogConfiguration: &ecstypes.LogConfiguration{
+				LogDriver: ecstypes.LogDriverAwslogs,
+				Options: map[string]string{
+					"awslogs-group":         logGroup(family),
+					"awslogs-region":        b.region,
+					"awslogs-stream-prefix": "ecs",
+				},
+			},
+		})
+	}
+	return &ecs.DescribeTaskDefinitionOutput{TaskDefinition: def}, nil
+}
+
+// ExecuteCommand is not supported: there is no container to open a session
+// in
+func (b *Backend) ExecuteCommand(ctx context.Context, params *ecs.ExecuteCommandInput, optFns ...func(*ecs.Options)) (*ecs.ExecuteCommandOutput, error) {
+	return nil, &ecstypes.InvalidParameterException{
+		Message: aws.String("ExecuteCommand is not supported by the fake backend."),
+	}
+}
+
+func invalidParameter(message string) error {
+	return &ecstypes.InvalidParameterException{Message: aws.String(message)}
+}
+
+// paginate returns the page of items starting at the offset in token, and
+// the token of the next page if there is one
+func paginate(items []string, token *string, maxResults *int32, defaultMax int32) ([]string, *string, error) {
+	start := 0
+	if token != nil {
+		var err error
+		start, err = strconv.Atoi(*token)
+		if err != nil || start < 0 || start > len(items) {
+			return nil, nil, invalidParameter("Invalid nextToken.")
+		}
+	}
+
+	limit := int(defaultMax)
+	if maxResults != nil {
+		if *maxResults < 1 || *maxResults > 100 {
+			return nil, nil, invalidParameter("maxResults must be between 1 and 100.")
+		}
+		limit = int(*maxResults)
+	}
+
+	end := start + limit
+	if end >= len(items) {
+		return items[start:], nil, nil
+	}
+	return items[start:end], aws.String(strconv.Itoa(end)), nil
+}
@@ -0,0 +1,145 @@
+// pkg/fake/fixture.go
+package fake
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Fixture describes the initial state of a fake backend
+type Fixture struct {
+	Region   string           `yaml:"region,omitempty"`
+	Clusters []ClusterFixture `yaml:"clusters"`
+}
+
+// ClusterFixture describes a cluster and the services running in it
+type ClusterFixture struct {
+	Name     string           `yaml:"name"`
+	Services []ServiceFixture `yaml:"services"`
+}
+
+// ServiceFixture describes a service. It starts with DesiredCount running
+// tasks of a task definition named after the service.
+type ServiceFixture struct {
+	Name         string             `yaml:"name"`
+	DesiredCount int32              `yaml:"desiredCount"`
+	LaunchType   string             `yaml:"launchType,omitempty"`
+	Cpu          string             `yaml:"cpu,omitempty"`
+	Memory       string             `yaml:"memory,omitempty"`
+	Containers   []ContainerFixture `yaml:"containers"`
+}
+
+// ContainerFixture describes a container of a service's task definition
+type ContainerFixture struct {
+	Name  string `yaml:"name"`
+	Image string `yaml:"image"`
+
+	// Logs are the lines every task of the service has logged for the
+	// container when it is started
+	Logs []string `yaml:"logs,omitempty"`
+}
+
+// LoadFixture reads a fixture file
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var f Fixture
+	if err := yaml.UnmarshalStrict(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid fixture %s: %w", path, err)
+	}
+	if err := f.validate(); err != nil {
+		return nil, fmt.Errorf("invalid fixture %s: %w", path, err)
+	}
+	return &f, nil
+}
+
+func (f *Fixture) validate() error {
+	clusters := make(map[string]bool)
+	for _, c := range f.Clusters {
+		if c.Name == "" {
+			return fmt.Errorf("cluster name cannot be empty")
+		}
+		if clusters[c.Name] {
+			return fmt.Errorf("cluster %q is defined twice", c.Name)
+		}
+		clusters[c.Name] = true
+
+		services := make(map[string]bool)
+		for _, s := range c.Services {
+			if s.Name == "" {
+				return fmt.Errorf("cluster %q: service name cannot be empty", c.Name)
+			}
+			if services[s.Name] {
+				return fmt.Errorf("cluster %q: service %q is defined twice", c.Name, s.Name)
+			}
+			services[s.Name] = true
+			if len(s.Containers) == 0 {
+				return fmt.Errorf("cluster %q: service %q has no containers", c.Name, s.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// demoCluster is the cluster every context gets from the demo backend
+var demoCluster = ClusterFixture{
+	Name: "demo",
+	Services: []ServiceFixture{
+		{
+			Name:         "web",
+			DesiredCount: 3,
+			Containers: []ContainerFixture{
+				{
+					Name:  "nginx",
+					Image: "public.ecr.aws/nginx/nginx:1.27",
+					Logs: []string{
+						"start worker processes",
+						`10.0.1.12 - - "GET / HTTP/1.1" 200 615`,
+						`10.0.2.40 - - "GET /healthz HTTP/1.1" 200 2`,
+						`10.0.1.12 - - "GET /static/app.js HTTP/1.1" 304 0`,
+					},
+				},
+			},
+		},
+		{
+			Name:         "api",
+			DesiredCount: 2,
+			Cpu:          "512",
+			Memory:       "1024",
+			Containers: []ContainerFixture{
+				{
+					Name:  "app",
+					Image: "123456789012.dkr.ecr.us-east-1.amazonaws.com/api:2.4.1",
+					Logs: []string{
+						"listening on :8080",
+						"GET /v1/orders 200 12ms",
+						"POST /v1/orders 201 48ms",
+					},
+				},
+				{
+					Name:  "ecs-service-connect-proxy",
+					Image: "public.ecr.aws/appmesh/aws-appmesh-envoy:v1.29",
+				},
+			},
+		},
+		{
+			Name:         "worker",
+			DesiredCount: 1,
+			Containers: []ContainerFixture{
+				{
+					Name:  "worker",
+					Image: "123456789012.dkr.ecr.us-east-1.amazonaws.com/worker:2.4.1",
+					Logs: []string{
+						"polling queue orders-events",
+						"processed 25 messages",
+					},
+				},
+			},
+		},
+	},
+}
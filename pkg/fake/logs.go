@@ -0,0 +1,64 @@
+// pkg/fake/logs.go
+package fake
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	cwltypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+// GetLogEvents returns the events a task's container logged, oldest first.
+// Forward tokens are of the form f/N, N being the index of the next event.
+func (b *Backend) GetLogEvents(ctx context.Context, params *cloudwatchlogs.GetLogEventsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.GetLogEventsOutput, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	events, ok := b.logs[logKey(aws.ToString(params.LogGroupName), aws.ToString(params.LogStreamName))]
+	if !ok {
+		return nil, &cwltypes.ResourceNotFoundException{Message: aws.String("The specified log stream does not exist.")}
+	}
+
+	start := 0
+	if params.NextToken != nil {
+		n, err := strconv.Atoi(strings.TrimPrefix(*params.NextToken, "f/"))
+		if err != nil || !strings.HasPrefix(*params.NextToken, "f/") || n < 0 || n > len(events) {
+			return nil, &cwltypes.InvalidParameterException{Message: aws.String("The specified nextToken is invalid.")}
+		}
+		start = n
+	}
+
+	limit := len(events)
+	if params.Limit != nil && int(*params.Limit) < limit {
+		limit = int(*params.Limit)
+	}
+
+	out := &cloudwatchlogs.GetLogEventsOutput{}
+	next := start
+	for ; next < len(events) && len(out.Events) < limit; next++ {
+		e := events[next]
+		if params.StartTime != nil && e.timestamp < *params.StartTime {
+			continue
+		}
+		if params.EndTime != nil && e.timestamp >= *params.EndTime {
+			continue
+		}
+		out.Events = append(out.Events, cwltypes.OutputLogEvent{
+			Timestamp:     aws.Int64(e.timestamp),
+			IngestionTime: aws.Int64(e.timestamp),
+			Message:       aws.String(e.message),
+		})
+	}
+	out.NextForwardToken = aws.String("f/" + strconv.Itoa(next))
+	out.NextBackwardToken = aws.String("b/" + strconv.Itoa(start))
+	return out, nil
+}
+
+// TerminateSession does nothing, as the fake backend never starts sessions
+func (b *Backend) TerminateSession(ctx context.Context, params *ssm.TerminateSessionInput, optFns ...func(*ssm.Options)) (*ssm.TerminateSessionOutput, error) {
+	return &ssm.TerminateSessionOutput{SessionId: params.SessionId}, nil
+}
@@ -0,0 +1,26 @@
+// pkg/types/config.go
+package types
+
+// Config is the versioned document stored in an ECS CLI config file
+type Config struct {
+	APIVersion     string              `yaml:"apiVersion"`
+	Kind           string              `yaml:"kind"`
+	CurrentContext string              `yaml:"current-context"`
+	Contexts       map[string]*Context `yaml:"contexts"`
+
+	// Aliases maps a command name to the command line it expands to
+	Aliases map[string]string `yaml:"aliases,omitempty"`
+
+	// Defaults applies to every context unless the context overrides it
+	Defaults Defaults `yaml:"defaults,omitempty"`
+
+	// Extra keeps keys this version doesn't know about so they survive a save
+	Extra map[string]interface{} `yaml:",inline"`
+}
+
+// ContextBundle is a portable set of contexts exported for sharing
+type ContextBundle struct {
+	APIVersion string     `yaml:"apiVersion"`
+	Kind       string     `yaml:"kind"`
+	Contexts   []*Context `yaml:"contexts"`
+}
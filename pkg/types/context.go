@@ -1,10 +1,125 @@
 // pkg/types/context.go
 package types
 
+import "time"
+
 // Context represents an ECS CLI configuration context
 type Context struct {
-	Name    string `mapstructure:"name" yaml:"name"`
-	Cluster string `mapstructure:"cluster" yaml:"cluster"`
-	Profile string `mapstructure:"profile" yaml:"profile"`
-	Region  string `mapstructure:"region" yaml:"region"`
+	Name    string `yaml:"name"`
+	Cluster string `yaml:"cluster"`
+	Profile string `yaml:"profile"`
+	Region  string `yaml:"region"`
+
+	// Optional source of the base credentials, the profile when not set
+	Credentials Credentials `yaml:"credentials,omitempty"`
+
+	// Optional role assumed on top of the base credentials
+	RoleArn         string        `yaml:"role-arn,omitempty"`
+	ExternalID      string        `yaml:"external-id,omitempty"`
+	SessionName     string        `yaml:"session-name,omitempty"`
+	SessionDuration time.Duration `yaml:"session-duration,omitempty"`
+	MFASerial       string        `yaml:"mfa-serial,omitempty"`
+
+	// Optional IAM Identity Center settings. When set, credentials come from
+	// the SSO role instead of the profile.
+	SSO SSO `yaml:"sso,omitempty"`
+
+	// Optional endpoint overrides, e.g. for LocalStack or a mock server
+	EndpointURL string    `yaml:"endpoint-url,omitempty"`
+	Endpoints   Endpoints `yaml:"endpoints,omitempty"`
+
+	// Optional retry and client-side rate limiting settings
+	Retry Retry `yaml:"retry,omitempty"`
+
+	// Protected contexts ask for confirmation before changing resources
+	Protected bool `yaml:"protected,omitempty"`
+
+	// Optional command defaults, taking precedence over the global ones
+	Defaults Defaults `yaml:"defaults,omitempty"`
+
+	// Extra keeps keys this version doesn't know about so they survive a save
+	Extra map[string]interface{} `yaml:",inline"`
+}
+
+// Endpoints overrides the endpoint URL of individual services
+type Endpoints struct {
+	ECS  string `yaml:"ecs,omitempty"`
+	Logs string `yaml:"logs,omitempty"`
+	SSM  string `yaml:"ssm,omitempty"`
+}
+
+// Sources of the base credentials of a context
+const (
+	CredentialsFromProfile     = "profile"
+	CredentialsFromEnv         = "env"
+	CredentialsFromProcess     = "process"
+	CredentialsFromWebIdentity = "web-identity"
+)
+
+// Credentials selects where the base credentials of a context come from
+type Credentials struct {
+	Source string `yaml:"source,omitempty"`
+
+	// Process is a command printing credentials, like a credential_process
+	Process string `yaml:"process,omitempty"`
+
+	// WebIdentityTokenFile holds the OIDC token exchanged for the context's
+	// role. AWS_WEB_IDENTITY_TOKEN_FILE is used when it is empty.
+	WebIdentityTokenFile string `yaml:"web-identity-token-file,omitempty"`
+}
+
+// SSO holds the IAM Identity Center settings of a context
+type SSO struct {
+	StartURL  string `yaml:"start-url,omitempty"`
+	Region    string `yaml:"region,omitempty"`
+	AccountID string `yaml:"account-id,omitempty"`
+	RoleName  string `yaml:"role-name,omitempty"`
+
+	// Session names the token cache, like an sso-session of the AWS CLI.
+	// The start URL is used when it is empty.
+	Session string `yaml:"session,omitempty"`
+}
+
+// Retry modes of a context
+const (
+	RetryModeStandard = "standard"
+	RetryModeAdaptive = "adaptive"
+)
+
+// Retry tunes how the requests of a context are retried and rate limited,
+// e.g. for large clusters sharing the account's API quota with other tools
+type Retry struct {
+	// MaxAttempts includes the first attempt. The SDK default of 3 is used
+	// when it is zero.
+	MaxAttempts int `yaml:"max-attempts,omitempty"`
+
+	// Mode is standard (the default) or adaptive, which also slows down
+	// requests once they get throttled
+	Mode string `yaml:"mode,omitempty"`
+
+	// RateLimit is the maximum number of requests per second a command
+	// sends, retries included. There is no limit when zero.
+	RateLimit float64 `yaml:"rate-limit,omitempty"`
+
+	// Burst is how many requests can be sent at once before RateLimit
+	// applies. One second of requests is allowed when it is zero.
+	Burst int `yaml:"burst,omitempty"`
+}
+
+// Defaults holds values used for command flags that aren't given
+type Defaults struct {
+	Output string       `yaml:"output,omitempty"`
+	Logs   LogsDefaults `yaml:"logs,omitempty"`
+	Exec   ExecDefaults `yaml:"exec,omitempty"`
+}
+
+// LogsDefaults holds defaults for the logs command
+type LogsDefaults struct {
+	Since     time.Duration `yaml:"since,omitempty"`
+	Container string        `yaml:"container,omitempty"`
+}
+
+// ExecDefaults holds defaults for the exec command
+type ExecDefaults struct {
+	Container string `yaml:"container,omitempty"`
 }
@@ -13,4 +13,8 @@ type Service struct {
 	RunningCount int       `json:"runningCount" yaml:"runningCount"`
 	PendingCount int       `json:"pendingCount" yaml:"pendingCount"`
 	CreatedAt    time.Time `json:"createdAt" yaml:"createdAt"`
+
+	// Context names the context the service was listed from when several
+	// contexts are queried at once
+	Context string `json:"context,omitempty" yaml:"context,omitempty"`
 }
@@ -18,4 +18,8 @@ type Task struct {
 	Group                string    `json:"group" yaml:"group"`
 	ContainerInstanceArn string    `json:"containerInstanceArn" yaml:"containerInstanceArn"`
 	CapacityProvider     string    `json:"capacityProvider" yaml:"capacityProvider"`
+
+	// Context names the context the task was listed from when several
+	// contexts are queried at once
+	Context string `json:"context,omitempty" yaml:"context,omitempty"`
 }
@@ -4,46 +4,102 @@ package utils
 import (
 	"os"
 
+	"github.com/mattn/go-runewidth"
 	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/renderer"
+	"github.com/olekukonko/tablewriter/tw"
 )
 
+// columnPadding separates the columns of a table
+var columnPadding = tw.Padding{Right: "  ", Overwrite: true}
+
 // TableFormatter provides consistent table formatting across the application
 type TableFormatter struct {
-	table *tablewriter.Table
+	table     *tablewriter.Table
+	headers   []string
+	rows      [][]string
+	minWidths map[int]int
 }
 
 // NewTableFormatter creates a new table formatter with consistent styling
 func NewTableFormatter(headers []string) *TableFormatter {
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader(headers)
-
-	// Set consistent styling
-	table.SetBorder(false)
-	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
-	table.SetAlignment(tablewriter.ALIGN_LEFT)
-	table.SetCenterSeparator("")
-	table.SetColumnSeparator("")
-	table.SetRowSeparator("")
-	table.SetHeaderLine(false)
-	table.SetTablePadding("  ")
-	table.SetNoWhiteSpace(true)
+	// Set consistent styling: no borders or lines, left aligned columns
+	// separated by two spaces
+	table := tablewriter.NewTable(os.Stdout,
+		tablewriter.WithRenderer(renderer.NewBlueprint(tw.Rendition{
+			Borders: tw.BorderNone,
+			Settings: tw.Settings{
+				Lines:      tw.LinesNone,
+				Separators: tw.SeparatorsNone,
+			},
+		})),
+		tablewriter.WithHeaderAlignment(tw.AlignLeft),
+		tablewriter.WithRowAlignment(tw.AlignLeft),
+		tablewriter.WithHeaderPaddingPerColumn(padding(len(headers))),
+		tablewriter.WithRowPaddingPerColumn(padding(len(headers))),
+		tablewriter.WithRowAutoWrap(tw.WrapNone),
+	)
+	table.Header(headers)
 
 	return &TableFormatter{
-		table: table,
+		table:     table,
+		headers:   headers,
+		minWidths: make(map[int]int),
+	}
+}
+
+// padding separates columns without leaving trailing spaces after the last
+func padding(columns int) []tw.Padding {
+	paddings := make([]tw.Padding, columns)
+	for i := range paddings {
+		paddings[i] = columnPadding
+	}
+	if columns > 0 {
+		paddings[columns-1] = tw.PaddingNone
 	}
+	return paddings
 }
 
 // AppendRow adds a row to the table
 func (t *TableFormatter) AppendRow(row []string) {
+	t.rows = append(t.rows, row)
 	t.table.Append(row)
 }
 
 // SetColumnMinWidth sets minimum width for a column
 func (t *TableFormatter) SetColumnMinWidth(column int, width int) {
-	t.table.SetColMinWidth(column, width)
+	t.minWidths[column] = width
 }
 
 // Render displays the table
 func (t *TableFormatter) Render() {
+	// Column widths are fixed in tablewriter, so only the columns whose
+	// content is narrower than their minimum are given one
+	widths := tw.NewMapper[int, int]()
+	for column, width := range t.minWidths {
+		if t.contentWidth(column) < width {
+			if column < len(t.headers)-1 {
+				width += runewidth.StringWidth(columnPadding.Right)
+			}
+			widths.Set(column, width)
+		}
+	}
+	if widths.Len() > 0 {
+		t.table.Options(tablewriter.WithColumnWidths(widths))
+	}
 	t.table.Render()
 }
+
+// contentWidth returns the width of the widest cell of a column
+func (t *TableFormatter) contentWidth(column int) int {
+	width := 0
+	if column < len(t.headers) {
+		width = runewidth.StringWidth(t.headers[column])
+	}
+	for _, row := range t.rows {
+		if column < len(row) {
+			width = max(width, runewidth.StringWidth(row[column]))
+		}
+	}
+	return width
+}